@@ -1,5 +1,7 @@
 pragma solidity 0.5.7;
 
+import "./zeppelin/math/SafeMath.sol";
+
 
 /**
  * This Basket contract is essentially just a data structure; it represents the tokens and weights
@@ -21,6 +23,8 @@ pragma solidity 0.5.7;
 */
 
 contract Basket {
+    using SafeMath for uint256;
+
     address[] public tokens;
     mapping(address => uint256) public weights; // unit: aqToken/RSV
     mapping(address => bool) public has;
@@ -66,4 +70,21 @@ contract Basket {
     function size() external view returns(uint256) {
         return tokens.length;
     }
+
+    /// The index of `token` in `tokens`. Reverts if `token` isn't in the basket; check `has` first.
+    function indexOf(address token) external view returns(uint256) {
+        for (uint256 i = 0; i < tokens.length; i++) {
+            if (tokens[i] == token) {
+                return i;
+            }
+        }
+        revert("Basket: token not found");
+    }
+
+    /// Sum of the weights of all tokens in the basket. unit: aqToken/RSV
+    function weightsSum() external view returns(uint256 sum) {
+        for (uint256 i = 0; i < tokens.length; i++) {
+            sum = sum.add(weights[tokens[i]]);
+        }
+    }
 }
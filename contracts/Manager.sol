@@ -13,6 +13,10 @@ interface IVault {
     function withdrawTo(address, uint256, address) external;
 }
 
+interface IOracle {
+    function price(address) external view returns(uint256);
+}
+
 /**
  * The Manager contract is the point of contact between the Reserve ecosystem and the
  * surrounding world. It manages the Issuance and Redemption of RSV, a decentralized stablecoin
@@ -71,33 +75,56 @@ contract Manager is Ownable {
     IRSV public trustedRSV;
     IProposalFactory public trustedProposalFactory;
 
+    // If set, isFullyCollateralized values the Vault's holdings at this oracle's prices before
+    // issuance. Redemption and proposals ignore it.
+    IOracle public trustedOracle;
+
+    // A proposed replacement for trustedOracle, which the owner can accept once `now` reaches
+    // oracleReadyAt. An oracleReadyAt of zero means nothing is proposed.
+    IOracle public proposedOracle;
+    uint256 public oracleReadyAt;
+
     // Proposals
     mapping(uint256 => IProposal) public trustedProposals;
     uint256 public proposalsLength;
     uint256 public delay = 24 hours;
 
+    // The largest number of tokens a basket may hold. Bounds the gas cost of issue and redeem.
+    // Basket itself never holds more than 10 tokens.
+    uint256 public maxBasketSize = 10;
+
     // Controls
     bool public issuancePaused;
     bool public emergency;
 
     // The spread between issuance and redemption in basis points (BPS).
     uint256 public seigniorage;              // 0.1% spread -> 10 BPS. unit: BPS
+
+    // The fee on redemption in basis points (BPS). The fee stays in the Vault as extra collateral.
+    uint256 public redeemFee;                // unit: BPS
     uint256 constant BPS_FACTOR = 10000;     // This is what 100% looks like in BPS. unit: BPS
     uint256 constant WEIGHT_SCALE = 10**18; // unit: aqToken/qToken
+    uint256 constant PRICE_SCALE = 10**18;  // This is what full value looks like as a price.
 
     event ProposalsCleared();
 
     // RSV traded events
     event Issuance(address indexed user, uint256 indexed amount);
+    event IssuanceTo(address indexed payer, address indexed recipient, uint256 amount);
     event Redemption(address indexed user, uint256 indexed amount);
+    event RedeemFee(address indexed user, uint256 rsvAmount, uint256 fee);
 
     // Pause events
     event IssuancePausedChanged(bool indexed oldVal, bool indexed newVal);
     event EmergencyChanged(bool indexed oldVal, bool indexed newVal);
     event OperatorChanged(address indexed oldAccount, address indexed newAccount);
     event SeigniorageChanged(uint256 oldVal, uint256 newVal);
+    event RedeemFeeChanged(uint256 oldVal, uint256 newVal);
     event VaultChanged(address indexed oldVaultAddr, address indexed newVaultAddr);
+    event OracleProposed(address indexed newOracleAddr, uint256 readyAt);
+    event OracleChanged(address indexed oldOracleAddr, address indexed newOracleAddr);
     event DelayChanged(uint256 oldVal, uint256 newVal);
+    event MaxBasketSizeChanged(uint256 oldVal, uint256 newVal);
 
     // Proposals
     event WeightsProposed(uint256 indexed id,
@@ -160,11 +187,21 @@ contract Manager is Ownable {
         _;
     }
 
-    /// Modifies a function to run and complete only if the vault is collateralized.
+    /// Modifies a function to run and complete only if the vault holds the basket for the RSV
+    /// supply, ignoring the oracle. A token's price dropping doesn't stop holders redeeming, or
+    /// the operator rebalancing the basket.
     modifier vaultCollateralized() {
+        require(_holdsBasket(false), "undercollateralized");
+        _;
+        assert(_holdsBasket(false));
+    }
+
+    /// Like vaultCollateralized, but also requires that the vault is fully collateralized at
+    /// oracle prices before the function runs. New RSV can't be issued against devalued tokens.
+    modifier issuanceCollateralized() {
         require(isFullyCollateralized(), "undercollateralized");
         _;
-        assert(isFullyCollateralized());
+        assert(_holdsBasket(false));
     }
 
     // ========================= Public + External ============================
@@ -187,6 +224,28 @@ contract Manager is Ownable {
         trustedVault = IVault(newVaultAddress);
     }
 
+    /// Propose replacing the price oracle, or clearing it with the zero address. The current
+    /// oracle stays in use until the proposal is accepted, at least `delay` from now.
+    /// Replaces any earlier proposal.
+    function proposeOracle(address newOracleAddress) external onlyOwner {
+        proposedOracle = IOracle(newOracleAddress);
+        oracleReadyAt = now.add(delay);
+        emit OracleProposed(newOracleAddress, oracleReadyAt);
+    }
+
+    /// Accept the proposed oracle, once its delay has passed.
+    /// The new oracle must have a nonzero price for every token in the basket.
+    function acceptOracle() external onlyOwner {
+        require(oracleReadyAt != 0, "no oracle proposed");
+        require(now >= oracleReadyAt, "oracle delay not over");
+        _requirePriced(proposedOracle, trustedBasket);
+
+        emit OracleChanged(address(trustedOracle), address(proposedOracle));
+        trustedOracle = proposedOracle;
+        proposedOracle = IOracle(0);
+        oracleReadyAt = 0;
+    }
+
     /// Clear the list of proposals.
     function clearProposals() external onlyOperator {
         proposalsLength = 0;
@@ -206,15 +265,38 @@ contract Manager is Ownable {
         seigniorage = _seigniorage;
     }
 
+    /// Set the redemption fee, in BPS.
+    function setRedeemFee(uint256 _redeemFee) external onlyOwner {
+        require(_redeemFee <= 1000, "max redeem fee 10%");
+        emit RedeemFeeChanged(redeemFee, _redeemFee);
+        redeemFee = _redeemFee;
+    }
+
     /// Set the Proposal delay in hours.
     function setDelay(uint256 _delay) external onlyOwner {
         emit DelayChanged(delay, _delay);
         delay = _delay;
     }
 
-    /// Ensure that the Vault is fully collateralized.  That this is true should be an
-    /// invariant of this contract: it's true before and after every txn.
+    /// Set the largest number of tokens a basket may hold.
+    function setMaxBasketSize(uint256 _maxBasketSize) external onlyOwner {
+        require(_maxBasketSize >= 1, "max basket size must be at least 1");
+        emit MaxBasketSizeChanged(maxBasketSize, _maxBasketSize);
+        maxBasketSize = _maxBasketSize;
+    }
+
+    /// Ensure that the Vault is fully collateralized. Ignoring prices, that this is true should be
+    /// an invariant of this contract: it's true before and after every txn.
+    /// If there is an oracle, each token's holdings count only at its oracle price, so a token
+    /// trading below the value the basket assumes can leave the Vault undercollateralized.
+    /// Prices above full value count as full value, so they never hide a shortfall.
     function isFullyCollateralized() public view returns(bool) {
+        return _holdsBasket(address(trustedOracle) != address(0));
+    }
+
+    /// @dev Whether the Vault holds enough of each basket token to back the RSV supply. If
+    /// `atOraclePrices`, each token's holdings count only at its oracle price.
+    function _holdsBasket(bool atOraclePrices) internal view returns(bool) {
         uint256 scaleFactor = WEIGHT_SCALE.mul(uint256(10) ** trustedRSV.decimals());
         // scaleFactor unit: aqToken/qToken * qRSV/RSV
 
@@ -223,6 +305,14 @@ contract Manager is Ownable {
             address trustedToken = trustedBasket.tokens(i);
             uint256 weight = trustedBasket.weights(trustedToken); // unit: aqToken/RSV
             uint256 balance = IERC20(trustedToken).balanceOf(address(trustedVault)); //unit: qToken
+            if (atOraclePrices) {
+                uint256 price = trustedOracle.price(trustedToken);
+                if (price > PRICE_SCALE) {
+                    price = PRICE_SCALE;
+                }
+                balance = balance.mul(price).div(PRICE_SCALE);
+                // unit: qToken, at full value
+            }
 
             // Return false if this token is undercollateralized:
             if (trustedRSV.totalSupply().mul(weight) > balance.mul(scaleFactor)) {
@@ -233,6 +323,33 @@ contract Manager is Ownable {
         return true;
     }
 
+    /// Get how far the Vault's holdings would drift from the basket after issuing `rsvAmount`.
+    /// For each token with nonzero weight, take the amount of RSV that the Vault's holdings of
+    /// that token could back, and compare it to the RSV supply; the drift is the sum of those
+    /// differences. A Vault holding exactly the basket for the RSV supply has zero drift.
+    /// Issuance adds tokens in basket proportions, so it leaves each of those differences
+    /// unchanged, except for rounding in the Vault's favor.
+    /// rsvAmount unit: qRSV. return unit: qRSV
+    function driftAfterIssue(uint256 rsvAmount) external view returns(uint256 drift) {
+        uint256 scaleFactor = WEIGHT_SCALE.mul(uint256(10) ** trustedRSV.decimals());
+        // scaleFactor unit: aqToken/qToken * qRSV/RSV
+        uint256 supply = trustedRSV.totalSupply().add(rsvAmount); // unit: qRSV
+        uint256[] memory amounts = toIssue(rsvAmount); // unit: qToken[]
+
+        for (uint256 i = 0; i < trustedBasket.size(); i++) {
+            address trustedToken = trustedBasket.tokens(i);
+            uint256 weight = trustedBasket.weights(trustedToken); // unit: aqToken/RSV
+            if (weight == 0) continue;
+
+            uint256 balance = IERC20(trustedToken).balanceOf(address(trustedVault)).add(amounts[i]);
+            // balance unit: qToken
+            uint256 backed = balance.mul(scaleFactor).div(weight);
+            // backed unit: qRSV == qToken * (aqToken/qToken * qRSV/RSV) / (aqToken/RSV)
+
+            drift = drift.add(backed > supply ? backed.sub(supply) : supply.sub(backed));
+        }
+    }
+
     /// Get amounts of basket tokens required to issue an amount of RSV.
     /// The returned array will be in the same order as the current basket.tokens.
     /// return unit: qToken[]
@@ -260,19 +377,64 @@ contract Manager is Ownable {
         return amounts; // unit: qToken[]
     }
 
+    /// Get the basket tokens and the amounts of each required to issue an amount of RSV, like
+    /// `toIssue`, along with each amount's rounding remainder. `toIssue` rounds the exact amount
+    /// of each token up to a whole qToken; remainders[i] is the fractional part of the exact
+    /// amount of token i, which is zero if and only if amounts[i] is exact.
+    /// return units: address[], qToken[], and (qToken / (WEIGHT_SCALE * 10**rsv.decimals()))[]
+    function quoteIssueWithRemainder(uint256 rsvAmount) external view returns(
+        address[] memory tokens,
+        uint256[] memory amounts,
+        uint256[] memory remainders
+    ) {
+        tokens = trustedBasket.getTokens();
+        amounts = toIssue(rsvAmount); // unit: qToken[]
+        remainders = new uint256[](tokens.length);
+
+        uint256 scaleFactor = WEIGHT_SCALE.mul(uint256(10) ** trustedRSV.decimals());
+        // scaleFactor unit: aqToken/qToken * qRSV/RSV
+        uint256 effectiveAmount = rsvAmount.mul(uint256(seigniorage.add(BPS_FACTOR))).div(BPS_FACTOR);
+        // effectiveAmount unit: qRSV
+
+        for (uint256 i = 0; i < tokens.length; i++) {
+            remainders[i] = effectiveAmount.mul(trustedBasket.weights(tokens[i])).mod(scaleFactor);
+            // unit: qRSV/RSV * aqToken, the same as _weighted's shiftedWeight
+        }
+    }
+
+    /// Get the basket tokens, and the amount of each that backs `rsvAmount` under the current
+    /// basket, rounded up to whole qTokens. This is what issuing `rsvAmount` costs with no
+    /// seigniorage; redeeming it with no redeem fee returns the same amounts, rounded down instead.
+    /// return units: address[], qToken[]
+    function backingForAmount(uint256 rsvAmount) external view returns(
+        address[] memory tokens,
+        uint256[] memory amounts
+    ) {
+        tokens = trustedBasket.getTokens();
+        amounts = new uint256[](tokens.length);
+        for (uint256 i = 0; i < tokens.length; i++) {
+            amounts[i] = _weighted(rsvAmount, trustedBasket.weights(tokens[i]), RoundingMode.UP);
+            // unit: qToken = _weighted(qRSV, aqToken/RSV, _)
+        }
+    }
+
     /// Get amounts of basket tokens that would be sent upon redeeming an amount of RSV.
     /// The returned array will be in the same order as the current basket.tokens.
+    /// The redeem fee is withheld from these amounts.
     /// return unit: qToken[]
     function toRedeem(uint256 rsvAmount) public view returns (uint256[] memory) {
         // rsvAmount unit: qRSV
         uint256[] memory amounts = new uint256[](trustedBasket.size());
 
+        uint256 effectiveAmount = rsvAmount.mul(BPS_FACTOR.sub(redeemFee)).div(BPS_FACTOR);
+        // effectiveAmount unit: qRSV == qRSV*BPS/BPS
+
         // On redemption, amounts[i] of token i will leave the vault. To maintain full backing,
         // we have to round _down_ each amounts[i].
         for (uint256 i = 0; i < trustedBasket.size(); i++) {
             address trustedToken = trustedBasket.tokens(i);
             amounts[i] = _weighted(
-                rsvAmount,
+                effectiveAmount,
                 trustedBasket.weights(trustedToken),
                 RoundingMode.DOWN
             );
@@ -282,13 +444,85 @@ contract Manager is Ownable {
         return amounts;
     }
 
+    /// Reports whether `issuer` could currently issue `rsvAmount` without reverting, and if not,
+    /// the reason why. Checks the same conditions as `issue`, including the RSV's own limits on
+    /// minting, without changing any state.
+    /// rsvAmount unit: qRSV
+    function canIssue(address issuer, uint256 rsvAmount) external view returns(bool, string memory) {
+        if (emergency) return (false, "contract is paused");
+        if (issuancePaused) return (false, "issuance is paused");
+        if (rsvAmount == 0) return (false, "cannot issue zero RSV");
+        if (trustedRSV.paused()) return (false, "RSV is paused");
+        if (trustedRSV.mintPaused()) return (false, "minting is paused");
+
+        uint256 maxMint = trustedRSV.maxMintPerTx(); // unit: qRSV
+        if (maxMint != 0 && rsvAmount > maxMint) return (false, "mint amount exceeds max");
+
+        // RSV requires its total supply to stay strictly below its max supply.
+        uint256 supply = trustedRSV.totalSupply(); // unit: qRSV
+        uint256 maxSupply = trustedRSV.maxSupply(); // unit: qRSV
+        if (supply >= maxSupply || rsvAmount >= maxSupply - supply) {
+            return (false, "max supply exceeded");
+        }
+
+        if (trustedBasket.size() == 0) return (false, "basket cannot be empty");
+        if (!isFullyCollateralized()) return (false, "undercollateralized");
+
+        uint256[] memory amounts = toIssue(rsvAmount); // unit: qToken[]
+        for (uint256 i = 0; i < trustedBasket.size(); i++) {
+            IERC20 trustedToken = IERC20(trustedBasket.tokens(i));
+            if (trustedToken.balanceOf(issuer) < amounts[i]) {
+                return (false, "insufficient collateral balance");
+            }
+            if (trustedToken.allowance(issuer, address(this)) < amounts[i]) {
+                return (false, "insufficient collateral allowance");
+            }
+        }
+        return (true, "");
+    }
+
+    /// Get the largest amount of RSV that `account` could redeem right now.
+    /// This is bounded by the account's RSV balance and its RSV allowance to this contract.
+    /// If RSV is paused or the Vault is short on any collateral token, redemption reverts,
+    /// so this returns 0. Oracle prices don't matter here, since they don't stop redemption.
+    /// return unit: qRSV
+    function maxRedeemable(address account) external view returns(uint256) {
+        if (emergency || trustedRSV.paused() || trustedBasket.size() == 0 || !_holdsBasket(false)) {
+            return 0;
+        }
+
+        uint256 amount = trustedRSV.balanceOf(account); // unit: qRSV
+        uint256 allowed = trustedRSV.allowance(account, address(this)); // unit: qRSV
+        if (allowed < amount) {
+            amount = allowed;
+        }
+        return amount;
+    }
+
     /// Handles issuance.
     /// rsvAmount unit: qRSV
     function issue(uint256 rsvAmount) external
         issuanceNotPaused
         notEmergency
-        vaultCollateralized
+        issuanceCollateralized
     {
+        _issue(_msgSender(), rsvAmount);
+    }
+
+    /// Handles issuance paid for by the sender, with the RSV minted to `recipient`.
+    /// Emits IssuanceTo, as well as Issuance, so that the payer is on record.
+    /// rsvAmount unit: qRSV
+    function issueTo(address recipient, uint256 rsvAmount) external
+        issuanceNotPaused
+        notEmergency
+        issuanceCollateralized
+    {
+        _issue(recipient, rsvAmount);
+        emit IssuanceTo(_msgSender(), recipient, rsvAmount);
+    }
+
+    /// @dev Takes collateral for `rsvAmount` from the sender and mints `rsvAmount` to `recipient`.
+    function _issue(address recipient, uint256 rsvAmount) internal {
         require(rsvAmount > 0, "cannot issue zero RSV");
         require(trustedBasket.size() > 0, "basket cannot be empty");
 
@@ -304,15 +538,32 @@ contract Manager is Ownable {
         }
 
         // Compensate with RSV.
-        trustedRSV.mint(_msgSender(), rsvAmount);
+        trustedRSV.mint(recipient, rsvAmount);
         // unit check for rsvAmount: qRSV.
 
-        emit Issuance(_msgSender(), rsvAmount);
+        emit Issuance(recipient, rsvAmount);
     }
 
     /// Handles redemption.
     /// rsvAmount unit: qRSV
     function redeem(uint256 rsvAmount) external notEmergency vaultCollateralized {
+        _redeem(rsvAmount, new uint256[](0));
+    }
+
+    /// Handles redemption, reverting if any token would be redeemed for less than the
+    /// corresponding entry of `minAmounts`, which is in basket order.
+    /// rsvAmount unit: qRSV, minAmounts unit: qToken[]
+    function redeemMin(uint256 rsvAmount, uint256[] calldata minAmounts) external
+        notEmergency
+        vaultCollateralized
+    {
+        require(minAmounts.length == trustedBasket.size(), "minAmounts length mismatch");
+        _redeem(rsvAmount, minAmounts);
+    }
+
+    /// @dev Burns `rsvAmount` from the sender and pays out the corresponding collateral. If
+    /// `minAmounts` is nonempty, each token's payout must be at least its entry.
+    function _redeem(uint256 rsvAmount, uint256[] memory minAmounts) internal {
         require(rsvAmount > 0, "cannot redeem 0 RSV");
         require(trustedBasket.size() > 0, "basket cannot be empty");
 
@@ -323,11 +574,20 @@ contract Manager is Ownable {
         // Compensate with collateral tokens.
         uint256[] memory amounts = toRedeem(rsvAmount); // unit: qToken[]
         for (uint256 i = 0; i < trustedBasket.size(); i++) {
+            require(
+                minAmounts.length == 0 || amounts[i] >= minAmounts[i],
+                "redemption below minimum"
+            );
             trustedVault.withdrawTo(trustedBasket.tokens(i), amounts[i], _msgSender());
             // unit check for amounts[i]: qToken.
         }
 
         emit Redemption(_msgSender(), rsvAmount);
+        if (redeemFee > 0) {
+            uint256 fee = rsvAmount.sub(rsvAmount.mul(BPS_FACTOR.sub(redeemFee)).div(BPS_FACTOR));
+            // fee unit: qRSV, the part of rsvAmount the Vault keeps collateral for.
+            emit RedeemFee(_msgSender(), rsvAmount, fee);
+        }
     }
 
     /**
@@ -375,6 +635,20 @@ contract Manager is Ownable {
     {
         require(tokens.length == amounts.length && amounts.length == toVault.length,
             "proposeSwap: unequal lengths");
+
+        // A swap keeps every token already in the basket, and adds any it doesn't have yet,
+        // counting a token listed more than once only once.
+        uint256 newSize = trustedBasket.size();
+        for (uint256 i = 0; i < tokens.length; i++) {
+            if (trustedBasket.has(tokens[i])) continue;
+            bool seen = false;
+            for (uint256 j = 0; j < i && !seen; j++) {
+                seen = tokens[j] == tokens[i];
+            }
+            if (!seen) newSize++;
+        }
+        require(newSize <= maxBasketSize, "proposeSwap: too many tokens");
+
         uint256 proposalID = proposalsLength++;
 
         trustedProposals[proposalID] = trustedProposalFactory.createSwapProposal(
@@ -405,6 +679,7 @@ contract Manager is Ownable {
     {
         require(tokens.length == weights.length, "proposeWeights: unequal lengths");
         require(tokens.length > 0, "proposeWeights: zero length");
+        require(tokens.length <= maxBasketSize, "proposeWeights: too many tokens");
 
         uint256 proposalID = proposalsLength++;
 
@@ -447,6 +722,8 @@ contract Manager is Ownable {
 
         // Complete proposal and compute new basket
         trustedBasket = trustedProposals[id].complete(trustedRSV, trustedOldBasket);
+        require(trustedBasket.size() <= maxBasketSize, "basket has too many tokens");
+        _requirePriced(trustedOracle, trustedBasket);
 
         // For each token in either basket, perform transfers between proposer and Vault
         for (uint256 i = 0; i < trustedOldBasket.size(); i++) {
@@ -482,6 +759,15 @@ contract Manager is Ownable {
 
     // ============================= Internal ================================
 
+    /// @dev Require that `oracle` has a nonzero price for every token in `basket`. With no
+    /// oracle, there is nothing to check.
+    function _requirePriced(IOracle oracle, Basket basket) internal view {
+        if (address(oracle) == address(0)) return;
+        for (uint256 i = 0; i < basket.size(); i++) {
+            require(oracle.price(basket.tokens(i)) > 0, "oracle must price every basket token");
+        }
+    }
+
     /// _executeBasketShift transfers the necessary amount of `token` between vault and `proposer`
     /// to rebalance the vault's balance of token, as it goes from oldBasket to newBasket.
     /// @dev To carry out a proposal, this is executed once per relevant token.
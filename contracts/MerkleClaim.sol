@@ -0,0 +1,55 @@
+pragma solidity 0.5.7;
+
+import "./zeppelin/token/ERC20/SafeERC20.sol";
+import "./zeppelin/token/ERC20/IERC20.sol";
+import "./ownership/Ownable.sol";
+
+/**
+ * @title A Merkle-proof airdrop of RSV
+ * @dev The owner publishes the root of a Merkle tree of (account, amount) claims, typically built
+ * off-chain from a snapshot of balances, and funds this contract with enough RSV to pay them.
+ * Each account can then claim its amount once, by proving its claim is in the tree.
+ *
+ * Each leaf is keccak256(abi.encodePacked(account, amount)). Each inner node is the hash of its
+ * two children, concatenated in sorted order, so a proof is just the list of sibling hashes from
+ * the leaf up to the root.
+ */
+contract MerkleClaim is Ownable {
+    using SafeERC20 for IERC20;
+
+    IERC20 public trustedToken;
+    bytes32 public merkleRoot;
+    mapping(address => bool) public claimed;
+
+    event MerkleRootChanged(bytes32 indexed oldRoot, bytes32 indexed newRoot);
+    event Claimed(address indexed account, uint256 amount);
+
+    constructor(address token) public {
+        trustedToken = IERC20(token);
+    }
+
+    /// Set the root of the claims tree.
+    function setMerkleRoot(bytes32 newRoot) external onlyOwner {
+        emit MerkleRootChanged(merkleRoot, newRoot);
+        merkleRoot = newRoot;
+    }
+
+    /// Claim `amount` tokens for the sender, proving the claim with `proof`.
+    function claim(uint256 amount, bytes32[] calldata proof) external {
+        require(!claimed[_msgSender()], "already claimed");
+
+        bytes32 node = keccak256(abi.encodePacked(_msgSender(), amount));
+        for (uint256 i = 0; i < proof.length; i++) {
+            if (node <= proof[i]) {
+                node = keccak256(abi.encodePacked(node, proof[i]));
+            } else {
+                node = keccak256(abi.encodePacked(proof[i], node));
+            }
+        }
+        require(node == merkleRoot, "invalid proof");
+
+        claimed[_msgSender()] = true;
+        emit Claimed(_msgSender(), amount);
+        trustedToken.safeTransfer(_msgSender(), amount);
+    }
+}
@@ -8,6 +8,15 @@ import "./ownership/Ownable.sol";
 /**
 * The Vault contract has an owner who is able to set the manager. The manager is
 * able to perform withdrawals. 
+*
+* As an escape hatch in case the manager is compromised, the owner can also withdraw, but only
+* `EMERGENCY_WITHDRAW_DELAY` after requesting that exact withdrawal.
+*
+* The delay doesn't bind the owner: the owner can make itself the manager with `changeManager`
+* and withdraw at once with `withdrawTo`. The owner is trusted with the collateral either way,
+* since it can always hand the Vault to a new manager; the delay only means an emergency
+* withdrawal is announced, by `EmergencyWithdrawRequested`, a day before it happens. Watch
+* `ManagerTransferred` as well to see every way collateral can leave the Vault.
 */
 contract Vault is Ownable {
     using SafeMath for uint256;
@@ -15,6 +24,12 @@ contract Vault is Ownable {
 
     address public manager;
 
+    uint256 public constant EMERGENCY_WITHDRAW_DELAY = 24 hours;
+
+    /// When each requested emergency withdrawal becomes executable, keyed by
+    /// `keccak256(abi.encodePacked(token, to, amount))`. Zero if it hasn't been requested.
+    mapping(bytes32 => uint256) public emergencyWithdrawReadyAt;
+
     event ManagerTransferred(
         address indexed previousManager,
         address indexed newManager
@@ -26,6 +41,19 @@ contract Vault is Ownable {
         address indexed to
     );
 
+    event EmergencyWithdrawRequested(
+        address indexed token,
+        address indexed to,
+        uint256 amount,
+        uint256 readyAt
+    );
+
+    event EmergencyWithdraw(
+        address indexed token,
+        address indexed to,
+        uint256 amount
+    );
+
     constructor() public {
         // Initialize manager as _msgSender()
         manager = _msgSender();
@@ -50,4 +78,37 @@ contract Vault is Ownable {
         IERC20(token).safeTransfer(to, amount);
         emit Withdrawal(token, amount, to);
     }
+
+    /// Request an emergency withdrawal of `amount` of `token` to address `to`, which the owner
+    /// can execute with `emergencyWithdraw` after `EMERGENCY_WITHDRAW_DELAY`. Requesting the
+    /// same withdrawal again restarts its delay.
+    function requestEmergencyWithdraw(address token, address to, uint256 amount)
+        external
+        onlyOwner
+    {
+        uint256 readyAt = now.add(EMERGENCY_WITHDRAW_DELAY);
+        emergencyWithdrawReadyAt[_emergencyWithdrawKey(token, to, amount)] = readyAt;
+        emit EmergencyWithdrawRequested(token, to, amount, readyAt);
+    }
+
+    /// Withdraw `amount` of `token` to address `to`, bypassing the manager. Only callable by the
+    /// owner, once `EMERGENCY_WITHDRAW_DELAY` has passed since requesting this withdrawal.
+    function emergencyWithdraw(address token, address to, uint256 amount) external onlyOwner {
+        bytes32 key = _emergencyWithdrawKey(token, to, amount);
+        uint256 readyAt = emergencyWithdrawReadyAt[key];
+        require(readyAt != 0, "withdrawal not requested");
+        require(now >= readyAt, "wait to withdraw");
+
+        delete emergencyWithdrawReadyAt[key];
+        IERC20(token).safeTransfer(to, amount);
+        emit EmergencyWithdraw(token, to, amount);
+    }
+
+    function _emergencyWithdrawKey(address token, address to, uint256 amount)
+        internal
+        pure
+        returns(bytes32)
+    {
+        return keccak256(abi.encodePacked(token, to, amount));
+    }
 }
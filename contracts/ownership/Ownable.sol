@@ -63,13 +63,35 @@ contract Ownable is Context {
         require(newOwner != address(0), "new owner is 0 address");
         emit NewOwnerNominated(_owner, newOwner);
         _nominatedOwner = newOwner;
+        _afterNominate(newOwner);
     }
 
+    /**
+     * @dev Clears the nominated owner, so that nobody can accept ownership until another
+     * nomination. Returns the nominee that was cleared.
+     */
+    function _clearNomination() internal returns (address nominee) {
+        nominee = _nominatedOwner;
+        _nominatedOwner = address(0);
+    }
+
+    /**
+     * @dev Hook called after `nominee` is nominated as the new owner. Does nothing by default.
+     */
+    function _afterNominate(address nominee) internal {}
+
+    /**
+     * @dev Hook called before the nominee accepts ownership, which it can prevent by reverting.
+     * Does nothing by default.
+     */
+    function _beforeAccept() internal view {}
+
     /**
      * @dev Accepts ownership of the contract.
      */
     function acceptOwnership() external {
         require(_nominatedOwner == _msgSender(), "unauthorized");
+        _beforeAccept();
         emit OwnershipTransferred(_owner, _nominatedOwner);
         _owner = _nominatedOwner;
     }
@@ -15,4 +15,10 @@ interface IRSV {
     function decimals() external view returns(uint8);
     function mint(address, uint256) external;
     function burnFrom(address, uint256) external;
+
+    // Conditions under which `mint` reverts
+    function paused() external view returns(bool);
+    function mintPaused() external view returns(bool);
+    function maxMintPerTx() external view returns(uint256);
+    function maxSupply() external view returns(uint256);
 }
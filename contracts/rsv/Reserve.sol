@@ -1,6 +1,7 @@
 pragma solidity 0.5.7;
 
 import "../zeppelin/token/ERC20/IERC20.sol";
+import "../zeppelin/token/ERC20/SafeERC20.sol";
 import "../zeppelin/math/SafeMath.sol";
 import "../ownership/Ownable.sol";
 import "./ReserveEternalStorage.sol";
@@ -12,6 +13,20 @@ import "./ReserveEternalStorage.sol";
      function calculateFee(address from, address to, uint256 amount) external returns (uint256);
  }
 
+/**
+ * @title Interfaces for contracts that accept ERC-1363 transfers and approvals
+ * @dev See [ERC-1363](https://eips.ethereum.org/EIPS/eip-1363).
+ */
+interface IERC1363Receiver {
+    function onTransferReceived(address operator, address from, uint256 value, bytes calldata data)
+        external returns (bytes4);
+}
+
+interface IERC1363Spender {
+    function onApprovalReceived(address owner, uint256 value, bytes calldata data)
+        external returns (bytes4);
+}
+
 /**
  * @title The Reserve Token
  * @dev An ERC-20 token with minting, burning, pausing, and user freezing.
@@ -21,6 +36,7 @@ import "./ReserveEternalStorage.sol";
  */
 contract Reserve is IERC20, Ownable {
     using SafeMath for uint256;
+    using SafeERC20 for IERC20;
 
 
     // ==== State ====
@@ -36,14 +52,44 @@ contract Reserve is IERC20, Ownable {
     uint256 public totalSupply;
     uint256 public maxSupply;
 
+    // Largest single transfer allowed, for accounts that aren't exempt. Zero means no limit.
+    uint256 public maxTransferAmount;
+
+    // Largest single mint allowed, to limit the damage a compromised minter key can do. Zero
+    // means no limit.
+    uint256 public maxMintPerTx;
+
     // Paused data
     bool public paused;
 
+    // Whether minting is paused. Independent of `paused`, so that minting can be stopped while
+    // transfers go on.
+    bool public mintPaused;
+
+    // Activity counters: how many times mint and burn have run. Keeping them costs an extra
+    // storage write on each mint and burn: 5000 gas, or 20000 gas for the first of each.
+    uint256 public mintCount;
+    uint256 public burnCount;
+
     // Auth roles
     address public minter;
     address public pauser;
     address public feeRecipient;
 
+    // Nominees for two-step role transfers
+    address public nominatedMinter;
+    address public nominatedPauser;
+
+    // The chain ID in this contract's EIP-712 domain. Solidity 0.5.7 can't read the chain ID, and
+    // Reserves can have the same address on several chains, so the owner sets it once; signed
+    // authorizations can't be used until then, so they can't be replayed across chains.
+    uint256 public chainId;
+
+    // How long a nominated new owner must wait before accepting ownership, and when the current
+    // nominee can accept it. A delay gives holders time to react to a pending handoff.
+    uint256 public handoffDelay;
+    uint256 public handoffReadyAt;
+
 
     // ==== Events, Constants, and Constructor ====
 
@@ -52,19 +98,63 @@ contract Reserve is IERC20, Ownable {
     event MinterChanged(address indexed newMinter);
     event PauserChanged(address indexed newPauser);
     event FeeRecipientChanged(address indexed newFeeRecipient);
+    event MinterNominated(address indexed nominee);
+    event PauserNominated(address indexed nominee);
     event MaxSupplyChanged(uint256 indexed newMaxSupply);
+    event MaxTransferAmountChanged(uint256 indexed newMaxTransferAmount);
+    event MaxMintPerTxChanged(uint256 indexed newMaxMintPerTx);
+    event MaxTransferExemptChanged(address indexed account, bool indexed exempt);
+    event TransferCapChanged(address indexed account, uint256 cap);
     event EternalStorageTransferred(address indexed newReserveAddress);
+    event EternalStorageChanged(
+        address indexed oldEternalStorage,
+        address indexed newEternalStorage
+    );
+    event HandoffCancelled(address indexed nominee);
+    event HandoffDelayChanged(uint256 indexed newHandoffDelay);
+    event ChainIdSet(uint256 indexed chainId);
+    event TokenReclaimed(address indexed token, address indexed to, uint256 value);
     event TxFeeHelperChanged(address indexed newTxFeeHelper);
 
+    // Mint events
+    event MintRef(address indexed recipient, uint256 amount, bytes32 indexed ref);
+
     // Pause events
     event Paused(address indexed account);
     event Unpaused(address indexed account);
+    event MintingPaused(address indexed account);
+    event MintingUnpaused(address indexed account);
+
+    // EIP-3009 authorization events
+    event AuthorizationUsed(address indexed authorizer, bytes32 indexed nonce);
+    event AuthorizationCanceled(address indexed authorizer, bytes32 indexed nonce);
 
     // Basic information as constants
     string public constant name = "Reserve";
     string public constant symbol = "RSV";
     uint8 public constant decimals = 18;
 
+    // Length of the window that per-account transfer caps apply to.
+    uint256 public constant TRANSFER_CAP_WINDOW = 24 hours;
+
+    // EIP-712 and EIP-3009 constants.
+    string internal constant EIP712_VERSION = "1";
+    bytes32 internal constant EIP712_DOMAIN_TYPEHASH = keccak256(
+        "EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"
+    );
+    bytes32 public constant TRANSFER_WITH_AUTHORIZATION_TYPEHASH = keccak256(
+        "TransferWithAuthorization(address from,address to,uint256 value,uint256 validAfter,uint256 validBefore,bytes32 nonce)"
+    );
+    bytes32 public constant CANCEL_AUTHORIZATION_TYPEHASH = keccak256(
+        "CancelAuthorization(address authorizer,bytes32 nonce)"
+    );
+
+    // ERC-1363 magic values, which receivers return to accept transfers and approvals.
+    // bytes4(keccak256("onTransferReceived(address,address,uint256,bytes)"))
+    bytes4 internal constant ERC1363_RECEIVED = 0x88a7ca5c;
+    // bytes4(keccak256("onApprovalReceived(address,uint256,bytes)"))
+    bytes4 internal constant ERC1363_APPROVED = 0x7b04a2d0;
+
     /// Initialize critical fields.
     constructor() public {
         pauser = msg.sender;
@@ -112,6 +202,36 @@ contract Reserve is IERC20, Ownable {
         emit PauserChanged(newPauser);
     }
 
+    /// Nominate `nominee` to take the `minter` role. The role only changes once the nominee
+    /// accepts it, with `acceptMinter`. `changeMinter` remains as a one-step emergency path.
+    function nominateMinter(address nominee) external onlyOwnerOr(minter) {
+        require(nominee != address(0), "nominee is zero address");
+        nominatedMinter = nominee;
+        emit MinterNominated(nominee);
+    }
+
+    /// Accept the `minter` role, as its nominee.
+    function acceptMinter() external only(nominatedMinter) {
+        minter = nominatedMinter;
+        nominatedMinter = address(0);
+        emit MinterChanged(minter);
+    }
+
+    /// Nominate `nominee` to take the `pauser` role. The role only changes once the nominee
+    /// accepts it, with `acceptPauser`. `changePauser` remains as a one-step emergency path.
+    function nominatePauser(address nominee) external onlyOwnerOr(pauser) {
+        require(nominee != address(0), "nominee is zero address");
+        nominatedPauser = nominee;
+        emit PauserNominated(nominee);
+    }
+
+    /// Accept the `pauser` role, as its nominee.
+    function acceptPauser() external only(nominatedPauser) {
+        pauser = nominatedPauser;
+        nominatedPauser = address(0);
+        emit PauserChanged(pauser);
+    }
+
     function changeFeeRecipient(address newFeeRecipient) external onlyOwnerOr(feeRecipient) {
         feeRecipient = newFeeRecipient;
         emit FeeRecipientChanged(newFeeRecipient);
@@ -126,6 +246,41 @@ contract Reserve is IERC20, Ownable {
         trustedData.updateReserveAddress(newReserveAddress);
     }
 
+    /// Switch this contract to a different EternalStorage contract, whose reserveAddress must
+    /// already be this contract. Data is not copied from the old storage to the new one: balances
+    /// and allowances are read from the new storage as-is, while totalSupply is unchanged. So the
+    /// new storage must be fully migrated before switching to it.
+    function setEternalStorage(address newEternalStorage) external onlyOwner isPaused {
+        require(
+            ReserveEternalStorage(newEternalStorage).reserveAddress() == address(this),
+            "storage not owned by Reserve"
+        );
+        emit EternalStorageChanged(address(trustedData), newEternalStorage);
+        trustedData = ReserveEternalStorage(newEternalStorage);
+    }
+
+    /// Cancel a pending handoff, e.g. to a new implementation found to be buggy, by clearing the
+    /// nominated owner. The nominee can then no longer accept ownership.
+    function cancelHandoff() external onlyOwner {
+        emit HandoffCancelled(_clearNomination());
+    }
+
+    /// Change how long a newly nominated owner must wait before accepting ownership. Doesn't
+    /// affect a nomination that is already pending.
+    function setHandoffDelay(uint256 newHandoffDelay) external onlyOwner {
+        handoffDelay = newHandoffDelay;
+        emit HandoffDelayChanged(newHandoffDelay);
+    }
+
+    /// Send this contract's whole balance of `token`, e.g. tokens sent here by mistake, to `to`.
+    /// Can't reclaim RSV itself: RSV balances live in the eternal storage, not in token contracts.
+    function reclaimToken(address token, address to) external onlyOwner {
+        require(token != address(this), "can't reclaim RSV");
+        uint256 balance = IERC20(token).balanceOf(address(this));
+        IERC20(token).safeTransfer(to, balance);
+        emit TokenReclaimed(token, to, balance);
+    }
+
     /// Change the contract that helps with transaction fee calculation.
     function changeTxFeeHelper(address newTrustedTxFee) external onlyOwner {
         trustedTxFee = ITXFee(newTrustedTxFee);
@@ -138,6 +293,41 @@ contract Reserve is IERC20, Ownable {
         emit MaxSupplyChanged(newMaxSupply);
     }
 
+    /// Change the largest single transfer allowed. Zero means no limit.
+    function setMaxTransferAmount(uint256 newMaxTransferAmount) external onlyOwner {
+        maxTransferAmount = newMaxTransferAmount;
+        emit MaxTransferAmountChanged(newMaxTransferAmount);
+    }
+
+    /// Change the largest single mint allowed. Zero means no limit.
+    function setMaxMintPerTx(uint256 newMaxMintPerTx) external onlyOwner {
+        maxMintPerTx = newMaxMintPerTx;
+        emit MaxMintPerTxChanged(newMaxMintPerTx);
+    }
+
+    /// Change whether transfers to and from `account` are exempt from `maxTransferAmount`.
+    function setMaxTransferExempt(address account, bool exempt) external onlyOwner {
+        trustedData.setMaxTransferExempt(account, exempt);
+        emit MaxTransferExemptChanged(account, exempt);
+    }
+
+    /// @return whether transfers to and from `account` are exempt from `maxTransferAmount`.
+    function isMaxTransferExempt(address account) external view returns (bool) {
+        return trustedData.maxTransferExempt(account);
+    }
+
+    /// Limit `account` to sending `cap` attotokens per TRANSFER_CAP_WINDOW. Zero means no limit.
+    /// The cap applies even if `account` is exempt from `maxTransferAmount`.
+    function setTransferCap(address account, uint256 cap) external onlyOwner {
+        trustedData.setTransferCap(account, cap);
+        emit TransferCapChanged(account, cap);
+    }
+
+    /// @return how much `account` may send per TRANSFER_CAP_WINDOW. Zero means no limit.
+    function transferCap(address account) external view returns (uint256) {
+        return trustedData.transferCap(account);
+    }
+
     /// Pause the contract.
     function pause() external only(pauser) {
         paused = true;
@@ -150,6 +340,18 @@ contract Reserve is IERC20, Ownable {
         emit Unpaused(pauser);
     }
 
+    /// Pause minting, leaving transfers and burns alone.
+    function pauseMint() external only(pauser) {
+        mintPaused = true;
+        emit MintingPaused(pauser);
+    }
+
+    /// Unpause minting.
+    function unpauseMint() external only(pauser) {
+        mintPaused = false;
+        emit MintingUnpaused(pauser);
+    }
+
     /// Modifies a function to run only when the contract is paused.
     modifier isPaused() {
         require(paused, "contract is not paused");
@@ -186,6 +388,29 @@ contract Reserve is IERC20, Ownable {
         return true;
     }
 
+    /// Transfer `value` attoRSV from `msg.sender` to `to`, reverting if the transaction fee
+    /// would leave `to` with less than `minReceived` attoRSV.
+    function transferExpecting(address to, uint256 value, uint256 minReceived)
+        external
+        notPaused
+        returns (bool)
+    {
+        require(_transfer(msg.sender, to, value) >= minReceived, "received less than minimum");
+        return true;
+    }
+
+    /// Transfer all of `msg.sender`'s attoRSV to `to`.
+    function transferAll(address to)
+        external
+        notPaused
+        returns (bool)
+    {
+        uint256 value = trustedData.balance(msg.sender);
+        require(value > 0, "no balance to transfer");
+        _transfer(msg.sender, to, value);
+        return true;
+    }
+
     /**
      * Approve `spender` to spend `value` attotokens on behalf of `msg.sender`.
      *
@@ -259,12 +484,17 @@ contract Reserve is IERC20, Ownable {
         notPaused
         only(minter)
     {
-        require(account != address(0), "can't mint to address zero");
+        _mint(account, value);
+    }
 
-        totalSupply = totalSupply.add(value);
-        require(totalSupply < maxSupply, "max supply exceeded");
-        trustedData.addBalance(account, value);
-        emit Transfer(address(0), account, value);
+    /// Mint `value` new attotokens to `account`, tagging the mint with an off-chain reference.
+    function mintWithRef(address account, uint256 value, bytes32 ref)
+        external
+        notPaused
+        only(minter)
+    {
+        _mint(account, value);
+        emit MintRef(account, value, ref);
     }
 
     /// Burn `value` attotokens from `account`, if sender has that much allowance from `account`.
@@ -277,10 +507,195 @@ contract Reserve is IERC20, Ownable {
         _approve(account, msg.sender, trustedData.allowed(account, msg.sender).sub(value));
     }
 
+    /// Burn `value` attotokens from the sender's own balance.
+    function burn(uint256 value) external notPaused {
+        _burn(msg.sender, value);
+    }
+
+    // ==== ERC-1363 transfers and approvals with callbacks ====
+
+
+    /// Transfer `value` attotokens from `msg.sender` to `to`, and then notify `to` by calling its
+    /// `onTransferReceived` with `data`. Reverts unless `to` is a contract that accepts the
+    /// transfer by returning the ERC-1363 magic value.
+    function transferAndCall(address to, uint256 value, bytes calldata data)
+        external
+        notPaused
+        returns (bool)
+    {
+        _transfer(msg.sender, to, value);
+        require(
+            IERC1363Receiver(to).onTransferReceived(msg.sender, msg.sender, value, data) ==
+            ERC1363_RECEIVED,
+            "receiver rejected transfer"
+        );
+        return true;
+    }
+
+    /// Approve `spender` to spend `value` attotokens on behalf of `msg.sender`, and then notify
+    /// `spender` by calling its `onApprovalReceived` with `data`. Reverts unless `spender` is a
+    /// contract that accepts the approval by returning the ERC-1363 magic value.
+    function approveAndCall(address spender, uint256 value, bytes calldata data)
+        external
+        notPaused
+        returns (bool)
+    {
+        _approve(msg.sender, spender, value);
+        require(
+            IERC1363Spender(spender).onApprovalReceived(msg.sender, value, data) ==
+            ERC1363_APPROVED,
+            "spender rejected approval"
+        );
+        return true;
+    }
+
+    // ==== EIP-3009 authorized transfers ====
+
+
+    /// @return the name in this contract's EIP-712 domain.
+    function eip712Name() external pure returns (string memory) {
+        return name;
+    }
+
+    /// @return the version in this contract's EIP-712 domain.
+    function eip712Version() external pure returns (string memory) {
+        return EIP712_VERSION;
+    }
+
+    /// Set the chain ID in this contract's EIP-712 domain. Can only be done once, and must be done
+    /// before signed authorizations can be used.
+    function setChainId(uint256 newChainId) external onlyOwner {
+        require(chainId == 0, "chain ID already set");
+        require(newChainId != 0, "chain ID is zero");
+        chainId = newChainId;
+        emit ChainIdSet(newChainId);
+    }
+
+    /// @return this contract's EIP-712 domain separator.
+    function DOMAIN_SEPARATOR() external view returns (bytes32) {
+        return _domainSeparator();
+    }
+
+    /// @return whether `authorizer` has used or canceled the authorization with nonce `nonce`.
+    function authorizationState(address authorizer, bytes32 nonce) external view returns (bool) {
+        return trustedData.authorizationUsed(authorizer, nonce);
+    }
+
+    /// Transfer `value` attotokens from `from` to `to`, as authorized by `from`'s signature
+    /// `(v, r, s)` over a TransferWithAuthorization message. The authorization is only valid
+    /// strictly after `validAfter` and strictly before `validBefore`, and can be used only once.
+    /// Anyone may submit the authorization, so `from` doesn't need to pay for gas.
+    function transferWithAuthorization(
+        address from,
+        address to,
+        uint256 value,
+        uint256 validAfter,
+        uint256 validBefore,
+        bytes32 nonce,
+        uint8 v,
+        bytes32 r,
+        bytes32 s
+    )
+        external
+        notPaused
+    {
+        require(now > validAfter, "authorization is not yet valid");
+        require(now < validBefore, "authorization is expired");
+
+        bytes memory data = abi.encode(
+            TRANSFER_WITH_AUTHORIZATION_TYPEHASH,
+            from,
+            to,
+            value,
+            validAfter,
+            validBefore,
+            nonce
+        );
+        require(_recover(v, r, s, data) == from, "invalid signature");
+
+        _useAuthorization(from, nonce);
+        _transfer(from, to, value);
+    }
+
+    /// Cancel `authorizer`'s unused authorization with nonce `nonce`, as authorized by
+    /// `authorizer`'s signature `(v, r, s)` over a CancelAuthorization message.
+    function cancelAuthorization(address authorizer, bytes32 nonce, uint8 v, bytes32 r, bytes32 s)
+        external
+        notPaused
+    {
+        bytes memory data = abi.encode(CANCEL_AUTHORIZATION_TYPEHASH, authorizer, nonce);
+        require(_recover(v, r, s, data) == authorizer, "invalid signature");
+
+        require(!trustedData.authorizationUsed(authorizer, nonce), "authorization is used");
+        trustedData.setAuthorizationUsed(authorizer, nonce);
+        emit AuthorizationCanceled(authorizer, nonce);
+    }
+
+    /// @dev Mark `authorizer`'s authorization with nonce `nonce` as used.
+    /// Reverts if it was already used or canceled.
+    function _useAuthorization(address authorizer, bytes32 nonce) internal {
+        require(!trustedData.authorizationUsed(authorizer, nonce), "authorization is used");
+        trustedData.setAuthorizationUsed(authorizer, nonce);
+        emit AuthorizationUsed(authorizer, nonce);
+    }
+
+    /// @dev The EIP-712 domain separator for this contract. Reverts until the chain ID is set.
+    function _domainSeparator() internal view returns (bytes32) {
+        require(chainId != 0, "chain ID not set");
+        return keccak256(abi.encode(
+            EIP712_DOMAIN_TYPEHASH,
+            keccak256(bytes(name)),
+            keccak256(bytes(EIP712_VERSION)),
+            chainId,
+            address(this)
+        ));
+    }
+
+    /// @dev Recover the signer of the EIP-712 message whose encoded type hash and data is
+    /// `typeHashAndData`. Rejects malleable signatures, as `ecrecover` does not.
+    function _recover(uint8 v, bytes32 r, bytes32 s, bytes memory typeHashAndData)
+        internal
+        view
+        returns (address)
+    {
+        require(
+            uint256(s) <= 0x7FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF5D576E7357A4501DDFE92F46681B20A0,
+            "invalid signature 's' value"
+        );
+        require(v == 27 || v == 28, "invalid signature 'v' value");
+
+        bytes32 digest = keccak256(abi.encodePacked(
+            "\x19\x01",
+            _domainSeparator(),
+            keccak256(typeHashAndData)
+        ));
+        address signer = ecrecover(digest, v, r, s);
+        require(signer != address(0), "invalid signature");
+        return signer;
+    }
+
+    /// @dev Start the handoff delay for a new owner nomination.
+    function _afterNominate(address) internal {
+        handoffReadyAt = now.add(handoffDelay);
+    }
+
+    /// @dev Only let the nominee accept ownership once the handoff delay has passed.
+    function _beforeAccept() internal view {
+        require(now >= handoffReadyAt, "handoff not ready");
+    }
+
     /// @dev Transfer of `value` attotokens from `from` to `to`.
     /// Internal; doesn't check permissions.
-    function _transfer(address from, address to, uint256 value) internal {
+    /// @return The number of attotokens `to` received, after the transaction fee.
+    function _transfer(address from, address to, uint256 value) internal returns (uint256) {
         require(to != address(0), "can't transfer to address zero");
+        require(to != address(this), "can't transfer to Reserve");
+        require(
+            maxTransferAmount == 0 || value <= maxTransferAmount ||
+            trustedData.maxTransferExempt(from) || trustedData.maxTransferExempt(to),
+            "transfer amount exceeds max"
+        );
+        _useTransferCap(from, value);
         trustedData.subBalance(from, value);
         uint256 fee = 0;
 
@@ -294,6 +709,39 @@ contract Reserve is IERC20, Ownable {
 
         trustedData.addBalance(to, value.sub(fee));
         emit Transfer(from, to, value.sub(fee));
+        return value.sub(fee);
+    }
+
+    /// @dev Count `value` against `from`'s transfer cap, if it has one.
+    /// A window starts with the first transfer after the previous window ends.
+    function _useTransferCap(address from, uint256 value) internal {
+        uint256 cap = trustedData.transferCap(from);
+        if (cap == 0) return;
+
+        uint256 start = trustedData.transferWindowStart(from);
+        uint256 transferred = trustedData.transferredInWindow(from);
+        if (now >= start.add(TRANSFER_CAP_WINDOW)) {
+            start = now;
+            transferred = 0;
+        }
+        transferred = transferred.add(value);
+        require(transferred <= cap, "transfer exceeds account's cap");
+        trustedData.setTransferWindow(from, start, transferred);
+    }
+
+    /// @dev Mint `value` new attotokens to `account`.
+    /// Internal; doesn't check permissions.
+    function _mint(address account, uint256 value) internal {
+        require(account != address(0), "can't mint to address zero");
+        require(account != address(this), "can't mint to Reserve");
+        require(!mintPaused, "minting is paused");
+        require(maxMintPerTx == 0 || value <= maxMintPerTx, "mint amount exceeds max");
+
+        totalSupply = totalSupply.add(value);
+        require(totalSupply < maxSupply, "max supply exceeded");
+        mintCount = mintCount.add(1);
+        trustedData.addBalance(account, value);
+        emit Transfer(address(0), account, value);
     }
 
     /// @dev Burn `value` attotokens from `account`.
@@ -302,6 +750,7 @@ contract Reserve is IERC20, Ownable {
         require(account != address(0), "can't burn from address zero");
 
         totalSupply = totalSupply.sub(value);
+        burnCount = burnCount.add(1);
         trustedData.subBalance(account, value);
         emit Transfer(account, address(0), value);
     }
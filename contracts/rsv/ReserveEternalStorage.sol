@@ -83,4 +83,51 @@ contract ReserveEternalStorage is Ownable {
     function setAllowed(address from, address to, uint256 value) external onlyReserveAddress {
         allowed[from][to] = value;
     }
+
+
+
+    // ===== maxTransferExempt =====
+
+    mapping(address => bool) public maxTransferExempt;
+
+    /// Set `maxTransferExempt[key]` to `exempt`.
+    function setMaxTransferExempt(address key, bool exempt) external onlyReserveAddress {
+        maxTransferExempt[key] = exempt;
+    }
+
+
+
+    // ===== accountTransferCaps =====
+
+    // Per-account limits on how much each account can send in a window, and each account's usage
+    // of its current window. Distinct from `maxTransferExempt`, which is about the Reserve's
+    // per-transfer `maxTransferAmount`.
+    mapping(address => uint256) public transferCap;
+    mapping(address => uint256) public transferWindowStart;
+    mapping(address => uint256) public transferredInWindow;
+
+    /// Set `transferCap[key]` to `cap`.
+    function setTransferCap(address key, uint256 cap) external onlyReserveAddress {
+        transferCap[key] = cap;
+    }
+
+    /// Set `key`'s current transfer window to start at `start`, with `transferred` sent so far.
+    function setTransferWindow(address key, uint256 start, uint256 transferred)
+        external
+        onlyReserveAddress
+    {
+        transferWindowStart[key] = start;
+        transferredInWindow[key] = transferred;
+    }
+
+
+
+    // ===== authorizations =====
+
+    mapping(address => mapping(bytes32 => bool)) public authorizationUsed;
+
+    /// Mark `authorizer`'s authorization with nonce `nonce` as used.
+    function setAuthorizationUsed(address authorizer, bytes32 nonce) external onlyReserveAddress {
+        authorizationUsed[authorizer][nonce] = true;
+    }
 }
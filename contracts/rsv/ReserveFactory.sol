@@ -0,0 +1,70 @@
+pragma solidity 0.5.7;
+
+import "./Reserve.sol";
+import "./ReserveEternalStorage.sol";
+
+/**
+ * @title A CREATE2 factory for the Reserve Token
+ * @dev Deploys Reserve contracts at addresses that depend only on this factory's address, the
+ * deployer's address, a deployer-chosen salt, and the Reserve init code. So, given the same
+ * factory address, a deployment is at the same address on every network.
+ *
+ * The init code is passed in by the caller, rather than embedded in this contract, so that the
+ * factory stays well under the EIP-170 code size limit however large Reserve grows.
+ *
+ * The deployer's address is mixed into the salt, so nobody else can front-run a deployment to
+ * a predicted address.
+ *
+ * Reserve's constructor gives all of its initial roles to its deployer, which is this factory.
+ * `deploy` hands each of them to the caller before returning: the caller becomes the Reserve's
+ * pauser and fee recipient, and is nominated as the owner of both the Reserve and its
+ * ReserveEternalStorage. The caller must then `acceptOwnership` on both contracts.
+ */
+contract ReserveFactory {
+    event ReserveDeployed(address indexed reserve, address indexed deployer, bytes32 indexed salt);
+
+    /// Deploy a new Reserve from `initCode` with CREATE2, and hand its roles to the caller.
+    /// `initCode` must be Reserve's creation code; if it deploys anything else, the role handoff
+    /// reverts. Reverts if the caller has already deployed this code with this `salt`.
+    function deploy(bytes32 salt, bytes calldata initCode) external returns(address) {
+        bytes memory code = initCode;
+        bytes32 fullSalt = _salt(msg.sender, salt);
+        address reserveAddress;
+        assembly {
+            reserveAddress := create2(0, add(code, 0x20), mload(code), fullSalt)
+        }
+        require(reserveAddress != address(0), "create2 failed");
+
+        Reserve reserve = Reserve(reserveAddress);
+        reserve.changePauser(msg.sender);
+        reserve.changeFeeRecipient(msg.sender);
+        reserve.nominateNewOwner(msg.sender);
+
+        ReserveEternalStorage data = ReserveEternalStorage(reserve.getEternalStorageAddress());
+        data.acceptOwnership();
+        data.nominateNewOwner(msg.sender);
+
+        emit ReserveDeployed(reserveAddress, msg.sender, salt);
+        return reserveAddress;
+    }
+
+    /// Compute the address at which `deployer` would deploy init code with hash `initCodeHash`,
+    /// using `salt`.
+    function computeAddress(address deployer, bytes32 salt, bytes32 initCodeHash)
+        external
+        view
+        returns(address)
+    {
+        bytes32 hash = keccak256(abi.encodePacked(
+            bytes1(0xff),
+            address(this),
+            _salt(deployer, salt),
+            initCodeHash
+        ));
+        return address(uint160(uint256(hash)));
+    }
+
+    function _salt(address deployer, bytes32 salt) internal pure returns(bytes32) {
+        return keccak256(abi.encodePacked(deployer, salt));
+    }
+}
@@ -0,0 +1,32 @@
+pragma solidity 0.5.7;
+
+
+/**
+ * Simple ERC-1363 receiver and spender for testing. It records each callback in an event, and
+ * accepts or rejects tokens according to `accept`.
+ */
+contract BasicERC1363Receiver {
+
+    bool accept;
+
+    event TransferReceived(address indexed operator, address indexed from, uint256 value, bytes data);
+    event ApprovalReceived(address indexed owner, uint256 value, bytes data);
+
+    constructor(bool _accept) public {
+        accept = _accept;
+    }
+
+    function onTransferReceived(address operator, address from, uint256 value, bytes calldata data)
+        external returns(bytes4)
+    {
+        emit TransferReceived(operator, from, value, data);
+        return accept ? bytes4(0x88a7ca5c) : bytes4(0);
+    }
+
+    function onApprovalReceived(address owner, uint256 value, bytes calldata data)
+        external returns(bytes4)
+    {
+        emit ApprovalReceived(owner, value, data);
+        return accept ? bytes4(0x7b04a2d0) : bytes4(0);
+    }
+}
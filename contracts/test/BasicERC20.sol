@@ -3,10 +3,64 @@ pragma solidity 0.5.7;
 import "../zeppelin/token/ERC20/ERC20.sol";
 
 /**
- * Simple ERC20 for testing. 
+ * Simple ERC20 for testing, with EIP-2612 permits.
+ *
+ * Solidity 0.5.7 can't read the chain ID, so the deployer passes in the ID of the chain its
+ * EIP-712 domain is for.
  */
 contract BasicERC20 is ERC20 {
-    constructor() public {
+    string public name;
+    string public symbol;
+    uint8 public decimals;
+
+    bytes32 public DOMAIN_SEPARATOR;
+    bytes32 public constant PERMIT_TYPEHASH = keccak256(
+        "Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"
+    );
+    mapping(address => uint256) public nonces;
+
+    constructor(string memory _name, string memory _symbol, uint8 _decimals, uint256 _chainId)
+        public
+    {
+        name = _name;
+        symbol = _symbol;
+        decimals = _decimals;
         _mint(msg.sender, 1e48);
+
+        DOMAIN_SEPARATOR = keccak256(abi.encode(
+            keccak256("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"),
+            keccak256(bytes(_name)),
+            keccak256(bytes("1")),
+            _chainId,
+            address(this)
+        ));
+    }
+
+    /// Approve `spender` to spend `value` of `owner`'s tokens, as authorized by `owner`'s
+    /// signature `(v, r, s)` over a Permit message. The permit is valid until `deadline`.
+    function permit(
+        address owner,
+        address spender,
+        uint256 value,
+        uint256 deadline,
+        uint8 v,
+        bytes32 r,
+        bytes32 s
+    ) external {
+        require(deadline >= now, "permit expired");
+        require(
+            uint256(s) <= 0x7FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF5D576E7357A4501DDFE92F46681B20A0,
+            "invalid signature 's' value"
+        );
+        bytes32 digest = keccak256(abi.encodePacked(
+            "\x19\x01",
+            DOMAIN_SEPARATOR,
+            keccak256(abi.encode(PERMIT_TYPEHASH, owner, spender, value, nonces[owner], deadline))
+        ));
+        address signer = ecrecover(digest, v, r, s);
+        require(signer != address(0) && signer == owner, "invalid signature");
+
+        nonces[owner]++;
+        _approve(owner, spender, value);
     }
 }
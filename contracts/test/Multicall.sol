@@ -0,0 +1,26 @@
+pragma solidity 0.5.7;
+pragma experimental ABIEncoderV2;
+
+
+/**
+ * Batches several view calls into one, so that tests against a remote node can read many values
+ * in a single round-trip. Based on MakerDAO's Multicall, but without the struct argument.
+ */
+contract Multicall {
+
+    /// Call each of `targets` with the corresponding entry of `data`, and return the results.
+    /// Reverts if any of the calls does.
+    function aggregate(address[] calldata targets, bytes[] calldata data)
+        external
+        view
+        returns(bytes[] memory results)
+    {
+        require(targets.length == data.length, "unequal array lengths");
+        results = new bytes[](targets.length);
+        for (uint256 i = 0; i < targets.length; i++) {
+            (bool success, bytes memory result) = targets[i].staticcall(data[i]);
+            require(success, "call failed");
+            results[i] = result;
+        }
+    }
+}
@@ -0,0 +1,21 @@
+pragma solidity 0.5.7;
+
+import "../ownership/Ownable.sol";
+
+/**
+ * A price oracle for testing, whose owner sets each price directly.
+ *
+ * Each price is a token's value as a fraction of the value the basket weights assume for it,
+ * scaled so that 1e18 is full value. A token without a price is worth nothing.
+ */
+contract Oracle is Ownable {
+    mapping(address => uint256) public price;
+
+    event PriceChanged(address indexed token, uint256 oldPrice, uint256 newPrice);
+
+    /// Set the price of `token`.
+    function setPrice(address token, uint256 newPrice) external onlyOwner {
+        emit PriceChanged(token, price[token], newPrice);
+        price[token] = newPrice;
+    }
+}
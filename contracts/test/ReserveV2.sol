@@ -10,6 +10,7 @@ contract ReserveV2 is Reserve {
 
     function completeHandoff(address previousImplementation) external onlyOwner {
         Reserve previous = Reserve(previousImplementation);
+        require(previous.nominatedOwner() == address(this), "not nominated by previous implementation");
         trustedData = ReserveEternalStorage(previous.getEternalStorageAddress());
         // Unpause.
         paused = false;
@@ -25,6 +26,7 @@ contract ReserveV2 is Reserve {
         // Burn the bridge behind us.
         previous.changeMinter(address(0));
         previous.changePauser(address(0));
+        previous.cancelHandoff(); // Clear our nomination, so it can't be accepted again.
         previous.renounceOwnership("I hereby renounce ownership of this contract forever.");
     }
 }
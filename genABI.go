@@ -92,6 +92,11 @@ func main() {
 		//
 		// We generate a String() function for each event and a
 		// Parse<ContractName>Log(*types.Log) function for each contract.
+		//
+		// ParseLog identifies events by their first topic, so it can't parse anonymous events,
+		// which don't have one. Events whose fields are all indexed have no data, only topics;
+		// UnpackLog handles those. Indexed strings and byte strings are only logged as their
+		// hashes, which String() prints in hex.
 		buf := new(bytes.Buffer)
 		parsedABI, err := abi.JSON(bytes.NewReader([]byte(output.ABI)))
 		check(err, "parsing ABI JSON")
@@ -100,8 +105,8 @@ func main() {
 				"flags": func(inputs abi.Arguments) string {
 					result := make([]string, len(inputs))
 					for i := range result {
-						switch inputs[i].Type.String() {
-						case "string":
+						switch {
+						case inputs[i].Type.String() == "string" && !inputs[i].Indexed:
 							result[i] = "%q"
 						default:
 							result[i] = "%v"
@@ -113,8 +118,10 @@ func main() {
 					result := make([]string, len(inputs))
 					for i := range result {
 						arg := "e." + abi.ToCamelCase(inputs[i].Name)
-						switch inputs[i].Type.String() {
-						case "address":
+						switch {
+						case inputs[i].Type.String() == "address":
+							arg = arg + ".Hex()"
+						case inputs[i].Indexed && isHashedTopic(inputs[i].Type):
 							arg = arg + ".Hex()"
 						}
 						result[i] = arg
@@ -136,14 +143,18 @@ func main() {
         {{$contract := .Contract}}
 
         func (c *{{$contract}}Filterer) ParseLog(log *types.Log) (fmt.Stringer, error) {
+            if len(log.Topics) == 0 {
+                return nil, fmt.Errorf("log has no topics, so it is not a {{$contract}} event")
+            }
+
             var event fmt.Stringer
             var eventName string
             switch log.Topics[0].Hex() {
-            {{- range .Events}}
+            {{- range .Events}}{{if not .Anonymous}}
             case {{with .Id}}{{printf "%q" .Hex}}{{end}}: // {{.Name}}
                 event = new({{$contract}}{{.Name}})
                 eventName = "{{.Name}}"
-            {{- end}}
+            {{- end}}{{end}}
             default:
                 return nil, fmt.Errorf("no such event hash for {{$contract}}: %v", log.Topics[0])
             }
@@ -177,6 +188,12 @@ func main() {
 	}
 }
 
+// isHashedTopic reports whether an indexed field of type t is bound as a common.Hash: strings
+// and byte strings are only logged as their hashes.
+func isHashedTopic(t abi.Type) bool {
+	return t.T == abi.StringTy || t.T == abi.BytesTy
+}
+
 func check(err error, msg string) {
 	if err != nil {
 		log.Fatal(msg, ": ", err)
@@ -0,0 +1,146 @@
+// Package ops contains helpers for deploying and operating the Reserve contracts from Go.
+// It builds on the generated bindings in package abi, which should not be edited by hand.
+package ops
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/reserve-protocol/rsv-beta/abi"
+)
+
+// DeployReserveDeterministic deploys a new Reserve through the ReserveFactory at `factory`,
+// using CREATE2 with `salt` and the Reserve binding's init code. It returns the Reserve's address, computed before the transaction
+// is mined, along with the deployment transaction and a binding to the new Reserve.
+//
+// Once the transaction is mined, opts.From holds the pauser and fee recipient roles, and is the
+// nominated owner of both the Reserve and its ReserveEternalStorage; it must call
+// AcceptOwnership on both.
+func DeployReserveDeterministic(
+	opts *bind.TransactOpts, backend bind.ContractBackend, factory common.Address, salt [32]byte,
+) (common.Address, *types.Transaction, *abi.Reserve, error) {
+	reserveFactory, err := abi.NewReserveFactory(factory, backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	tx, err := reserveFactory.Deploy(opts, salt, common.FromHex(abi.ReserveBin))
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	address := ReserveAddress(factory, opts.From, salt)
+	reserve, err := abi.NewReserve(address, backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, reserve, nil
+}
+
+// ReserveAddress computes the address at which `deployer` would deploy a Reserve through the
+// ReserveFactory at `factory`, using `salt`. It matches ReserveFactory.computeAddress given the
+// hash of the Reserve binding's init code.
+func ReserveAddress(factory, deployer common.Address, salt [32]byte) common.Address {
+	var fullSalt [32]byte
+	copy(fullSalt[:], crypto.Keccak256(deployer.Bytes(), salt[:]))
+	return crypto.CreateAddress2(factory, fullSalt, crypto.Keccak256(common.FromHex(abi.ReserveBin)))
+}
+
+// DeployBackend is a contract backend that can also wait for transactions to be mined.
+// Both *ethclient.Client and *backends.SimulatedBackend satisfy it.
+type DeployBackend interface {
+	bind.ContractBackend
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// ReserveDeployment holds the addresses of a Reserve system deployed by DeployReserveSystem.
+type ReserveDeployment struct {
+	Reserve        common.Address `json:"reserve"`
+	EternalStorage common.Address `json:"eternalStorage"`
+	Owner          common.Address `json:"owner"`
+	Minter         common.Address `json:"minter"`
+	Pauser         common.Address `json:"pauser"`
+	FeeRecipient   common.Address `json:"feeRecipient"`
+}
+
+// DeployReserveSystem deploys a new Reserve and its ReserveEternalStorage, and waits for each
+// step to be mined. opts.From accepts ownership of the eternal storage, and takes the minter,
+// pauser, and fee recipient roles; it already owns the Reserve. It sets the Reserve's EIP-712
+// chain ID to `chainID`, which must be the ID of the chain it's deployed on. Finally, it unpauses
+// the Reserve.
+//
+// Each step is a separate transaction. If one fails, DeployReserveSystem returns an error, and the
+// Reserve may be left partly set up.
+func DeployReserveSystem(
+	opts *bind.TransactOpts, backend DeployBackend, chainID *big.Int,
+) (ReserveDeployment, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var deployment ReserveDeployment
+
+	// mined(step)(tx, err) waits for `tx` to be mined, and checks that it succeeded. Like the
+	// test helpers, it's curried so that it can directly wrap the binding calls.
+	mined := func(step string) func(*types.Transaction, error) error {
+		return func(tx *types.Transaction, err error) error {
+			if err != nil {
+				return fmt.Errorf("%v: %v", step, err)
+			}
+			receipt, err := bind.WaitMined(ctx, backend, tx)
+			if err != nil {
+				return fmt.Errorf("%v: %v", step, err)
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("%v: transaction %v failed", step, tx.Hash().Hex())
+			}
+			return nil
+		}
+	}
+
+	reserveAddress, tx, reserve, err := abi.DeployReserve(opts, backend)
+	if err := mined("deploying Reserve")(tx, err); err != nil {
+		return deployment, err
+	}
+	deployment.Reserve = reserveAddress
+	deployment.Owner = opts.From
+
+	deployment.EternalStorage, err = reserve.GetEternalStorageAddress(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return deployment, fmt.Errorf("getting eternal storage address: %v", err)
+	}
+	eternalStorage, err := abi.NewReserveEternalStorage(deployment.EternalStorage, backend)
+	if err != nil {
+		return deployment, err
+	}
+	accept := mined("accepting eternal storage ownership")
+	if err := accept(eternalStorage.AcceptOwnership(opts)); err != nil {
+		return deployment, err
+	}
+
+	if err := mined("changing minter")(reserve.ChangeMinter(opts, opts.From)); err != nil {
+		return deployment, err
+	}
+	deployment.Minter = opts.From
+	if err := mined("changing pauser")(reserve.ChangePauser(opts, opts.From)); err != nil {
+		return deployment, err
+	}
+	deployment.Pauser = opts.From
+	if err := mined("changing fee recipient")(reserve.ChangeFeeRecipient(opts, opts.From)); err != nil {
+		return deployment, err
+	}
+	deployment.FeeRecipient = opts.From
+
+	if err := mined("setting chain ID")(reserve.SetChainId(opts, chainID)); err != nil {
+		return deployment, err
+	}
+
+	if err := mined("unpausing")(reserve.Unpause(opts)); err != nil {
+		return deployment, err
+	}
+	return deployment, nil
+}
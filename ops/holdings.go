@@ -0,0 +1,43 @@
+package ops
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/reserve-protocol/rsv-beta/abi"
+)
+
+// BalanceBackend is a contract backend that can also report ETH balances.
+// Both *ethclient.Client and *backends.SimulatedBackend satisfy it.
+type BalanceBackend interface {
+	bind.ContractCaller
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// ContractHoldings reports the ETH balance of `contract`, and its balance of each ERC20 token in
+// `tokens`, in the same order. It is meant for auditing that a contract holds no stray funds.
+func ContractHoldings(
+	backend BalanceBackend, contract common.Address, tokens []common.Address,
+) (eth *big.Int, balances []*big.Int, err error) {
+	eth, err = backend.BalanceAt(context.Background(), contract, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	balances = make([]*big.Int, len(tokens))
+	for i, token := range tokens {
+		// Any ERC20 will do here; we only need balanceOf.
+		erc20, err := abi.NewBasicERC20Caller(token, backend)
+		if err != nil {
+			return nil, nil, err
+		}
+		balances[i], err = erc20.BalanceOf(nil, contract)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return eth, balances, nil
+}
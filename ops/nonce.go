@@ -0,0 +1,79 @@
+package ops
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// NonceManager hands out consecutive nonces for one account, so that transactions sent in quick
+// succession, or concurrently, don't collide. Without it, each transaction asks the node for the
+// account's pending nonce, which can lag behind transactions that were only just sent.
+//
+// The first nonce is fetched from the node. After that, nonces are counted locally, so all of
+// the account's transactions must go through the same NonceManager. If a transaction fails to
+// send after taking a nonce, call Reset to resynchronize with the node.
+type NonceManager struct {
+	backend bind.ContractTransactor
+	address common.Address
+
+	mu    sync.Mutex
+	next  uint64
+	valid bool
+}
+
+// NewNonceManager returns a NonceManager for `address` on `backend`.
+func NewNonceManager(backend bind.ContractTransactor, address common.Address) *NonceManager {
+	return &NonceManager{backend: backend, address: address}
+}
+
+// Next reserves and returns the next nonce.
+func (m *NonceManager) Next(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.valid {
+		nonce, err := m.backend.PendingNonceAt(ctx, m.address)
+		if err != nil {
+			return 0, err
+		}
+		m.next, m.valid = nonce, true
+	}
+	nonce := m.next
+	m.next++
+	return nonce, nil
+}
+
+// Reset makes the next call to Next fetch the pending nonce from the node again.
+func (m *NonceManager) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.valid = false
+}
+
+// Wrap returns a copy of `opts` that numbers its transactions with nonces from m. opts.From must
+// be m's account. Any nonce set in opts is ignored.
+func (m *NonceManager) Wrap(opts *bind.TransactOpts) *bind.TransactOpts {
+	result := *opts
+	result.Nonce = nil
+	result.Signer = func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		ctx := opts.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		nonce, err := m.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// The binding has already filled in a nonce, so rebuild the transaction with ours.
+		if to := tx.To(); to != nil {
+			tx = types.NewTransaction(nonce, *to, tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data())
+		} else {
+			tx = types.NewContractCreation(nonce, tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data())
+		}
+		return opts.Signer(signer, address, tx)
+	}
+	return &result
+}
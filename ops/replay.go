@@ -0,0 +1,99 @@
+package ops
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/reserve-protocol/rsv-beta/abi"
+)
+
+// ReserveState is the token state of a Reserve, as reconstructed from its events.
+type ReserveState struct {
+	TotalSupply *big.Int
+	Balances    map[common.Address]*big.Int
+	Allowances  map[common.Address]map[common.Address]*big.Int // owner => spender => allowance
+}
+
+// RebuildState reconstructs the total supply, balances, and allowances of `reserve` by replaying
+// its Transfer and Approval events from block `fromBlock` onwards. Mints are Transfers from the
+// zero address, and burns are Transfers to it.
+//
+// The result only matches the on-chain state if `fromBlock` is no later than the Reserve's
+// deployment, and if the Reserve has not been handed a previously-used ReserveEternalStorage.
+func RebuildState(reserve *abi.Reserve, fromBlock uint64) (*ReserveState, error) {
+	state := &ReserveState{
+		TotalSupply: new(big.Int),
+		Balances:    make(map[common.Address]*big.Int),
+		Allowances:  make(map[common.Address]map[common.Address]*big.Int),
+	}
+	opts := &bind.FilterOpts{Start: fromBlock}
+
+	transfers, err := reserve.FilterTransfer(opts, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transfers.Close()
+	for transfers.Next() {
+		e := transfers.Event
+		if e.From == (common.Address{}) {
+			state.TotalSupply.Add(state.TotalSupply, e.Value)
+		} else {
+			state.balance(e.From).Sub(state.balance(e.From), e.Value)
+		}
+		if e.To == (common.Address{}) {
+			state.TotalSupply.Sub(state.TotalSupply, e.Value)
+		} else {
+			state.balance(e.To).Add(state.balance(e.To), e.Value)
+		}
+	}
+	if err := transfers.Error(); err != nil {
+		return nil, err
+	}
+
+	// Approval events carry the new allowance, so the latest event for each pair wins.
+	approvals, err := reserve.FilterApproval(opts, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer approvals.Close()
+	for approvals.Next() {
+		e := approvals.Event
+		if state.Allowances[e.Owner] == nil {
+			state.Allowances[e.Owner] = make(map[common.Address]*big.Int)
+		}
+		state.Allowances[e.Owner][e.Spender] = new(big.Int).Set(e.Value)
+	}
+	if err := approvals.Error(); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Spenders returns the spenders that `holder` has given nonzero allowances, sorted by address.
+// Reserve doesn't enumerate these on-chain, since keeping a list in storage would cost every
+// allowance change an extra write.
+func (state *ReserveState) Spenders(holder common.Address) []common.Address {
+	var spenders []common.Address
+	for spender, allowance := range state.Allowances[holder] {
+		if allowance.Sign() != 0 {
+			spenders = append(spenders, spender)
+		}
+	}
+	sort.Slice(spenders, func(i, j int) bool {
+		return bytes.Compare(spenders[i].Bytes(), spenders[j].Bytes()) < 0
+	})
+	return spenders
+}
+
+// balance returns the balance entry for `account`, creating it if necessary.
+func (state *ReserveState) balance(account common.Address) *big.Int {
+	if state.Balances[account] == nil {
+		state.Balances[account] = new(big.Int)
+	}
+	return state.Balances[account]
+}
@@ -0,0 +1,96 @@
+package ops
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/reserve-protocol/rsv-beta/abi"
+)
+
+// SimResult is the predicted outcome of a Manager.issue call.
+type SimResult struct {
+	// Reverted tells whether the call would revert. If it would, RevertReason holds the reason
+	// it gave, if any, and no RSV or collateral would move.
+	Reverted     bool
+	RevertReason string
+
+	// RSVMinted is the amount of RSV that the issuer would receive, in qRSV.
+	RSVMinted *big.Int
+	// Tokens are the basket tokens, and Collateral[i] is the amount of Tokens[i] that would
+	// move from the issuer to the Vault, in qTokens.
+	Tokens     []common.Address
+	Collateral []*big.Int
+}
+
+// SimulateIssue predicts the outcome of `issuer` calling `issue(amount)` on the Manager at
+// `manager`, against the latest block, without sending a transaction.
+//
+// The call is run with eth_call, so that everything `issue` checks -- pause state,
+// collateralization, and the issuer's balances and allowances -- is checked exactly as it would
+// be in a transaction.
+func SimulateIssue(
+	backend bind.ContractBackend, manager common.Address, issuer common.Address, amount *big.Int,
+) (*SimResult, error) {
+	managerABI, err := ethabi.JSON(strings.NewReader(abi.ManagerABI))
+	if err != nil {
+		return nil, err
+	}
+	data, err := managerABI.Pack("issue", amount)
+	if err != nil {
+		return nil, err
+	}
+	msg := ethereum.CallMsg{From: issuer, To: &manager, Data: data}
+
+	// Nodes don't agree on whether a reverted eth_call is an error, but estimating gas for a
+	// call that always fails is.
+	if _, err := backend.EstimateGas(context.Background(), msg); err != nil {
+		result := &SimResult{Reverted: true, RSVMinted: big.NewInt(0)}
+		output, err := backend.CallContract(context.Background(), msg, nil)
+		if err == nil {
+			result.RevertReason, _ = RevertReason(output)
+		}
+		return result, nil
+	}
+
+	caller, err := abi.NewManagerCaller(manager, backend)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := caller.QuoteIssueWithRemainder(nil, amount)
+	if err != nil {
+		return nil, err
+	}
+	return &SimResult{
+		RSVMinted:  new(big.Int).Set(amount),
+		Tokens:     quote.Tokens,
+		Collateral: quote.Amounts,
+	}, nil
+}
+
+// RevertReason decodes the reason string from the return data of a call that reverted with
+// `Error(string)`, as produced by Solidity's `require` and `revert`. It returns false if
+// `data` is not in that format.
+func RevertReason(data []byte) (string, bool) {
+	// The first four bytes of keccak256("Error(string)").
+	selector := []byte{0x08, 0xc3, 0x79, 0xa0}
+	if len(data) < 4 || !reflect.DeepEqual(data[:4], selector) {
+		return "", false
+	}
+
+	stringType, err := ethabi.NewType("string", nil)
+	if err != nil {
+		return "", false
+	}
+	var reason string
+	if err := (ethabi.Arguments{{Type: stringType}}).Unpack(&reason, data[4:]); err != nil {
+		return "", false
+	}
+	return reason, true
+}
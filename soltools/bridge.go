@@ -240,3 +240,28 @@ func (b *Backend) WriteCoverage() error {
 		new(bool), // ignore output
 	)
 }
+
+// WriteGasProfile writes a gas profile to $PWD/gas-profile/gas-profile.json.
+//
+// The profile attributes the gas used by every transaction and call made through this Backend to
+// the Solidity statements that used it. It only has data if GAS_PROFILE_ENABLED was set when the
+// Backend was created. The file is a JSON object keyed by source file path. Each value maps a
+// statement's source range, as "startLine:startColumn-endLine:endColumn", to the cumulative gas
+// used by that statement since the Backend was created. Lines are 1-based and columns are 0-based.
+// Statements that never ran are left out. Each call overwrites the file, so to profile several
+// test suites together, run them all against one Backend and write the profile once they're done.
+// For example:
+//
+//	{
+//	  "contracts/rsv/Reserve.sol": {
+//	    "312:8-312:51": 42210,
+//	    "313:8-313:25": 1563
+//	  }
+//	}
+func (b *Backend) WriteGasProfile() error {
+	return b.call(
+		"writeGasProfile",
+		true,      // ignored input
+		new(bool), // ignore output
+	)
+}
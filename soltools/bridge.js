@@ -1,12 +1,14 @@
 // This is the JavaScript end of the Go-JavaScript bridge implemented in this package.
 
 // Imports
+const fs = require('fs');
 const util = require('util');
 const http = require('http');
 const Web3 = require('web3');
 
 const { SolCompilerArtifactAdapter } = require('@0x/sol-trace');
 const { CoverageSubprovider } = require('@0x/sol-coverage');
+const { ProfilerSubprovider } = require('@0x/sol-profiler');
 const ProviderEngine = require('web3-provider-engine');
 const RpcSubprovider = require('web3-provider-engine/subproviders/rpc.js');
 
@@ -16,14 +18,22 @@ const [, , artifactsDir, contractsDir] = process.argv;
 // Create web3 provider chain.
 // We need an artifact adapter so the coverage subprovider knows how to map EVM traces source code.
 // We need a coverage subprovider so we can write a coverage report.
+// If GAS_PROFILE_ENABLED is set, we also need a profiler subprovider so we can write a gas profile.
 // We also need an RPC subprovider to handle everything else.
 const artifactAdapter = new SolCompilerArtifactAdapter(artifactsDir, contractsDir);
 const coverageSubprovider = new CoverageSubprovider(
   artifactAdapter,
   '0x5409ed021d9299bf6814279a6a1411a7e866a631'
 );
+const profilerSubprovider = process.env.GAS_PROFILE_ENABLED ? new ProfilerSubprovider(
+  artifactAdapter,
+  '0x5409ed021d9299bf6814279a6a1411a7e866a631'
+) : null;
 const provider = new ProviderEngine();
 provider.addProvider(coverageSubprovider);
+if (profilerSubprovider) {
+  provider.addProvider(profilerSubprovider);
+}
 provider.addProvider(new RpcSubprovider({rpcUrl: 'http://localhost:8545'}));
 provider.start();
 provider.stop();
@@ -46,6 +56,45 @@ function promisify(f) {
   }));
 }
 
+// gasBySourceRange converts sol-profiler's output, which is in Istanbul's coverage format with gas
+// in place of hit counts, into an object mapping each source file to an object mapping statement
+// source ranges to the cumulative gas they used. See WriteGasProfile in bridge.go.
+function gasBySourceRange(istanbul) {
+  const profile = {};
+  for (const [path, file] of Object.entries(istanbul)) {
+    const statements = {};
+    for (const [id, {start, end}] of Object.entries(file.statementMap)) {
+      if (file.s[id] > 0) {
+        statements[`${start.line}:${start.column}-${end.line}:${end.column}`] = file.s[id];
+      }
+    }
+    profile[path] = statements;
+  }
+  return profile;
+}
+
+// writeGasProfile writes the gas profile to gas-profile/gas-profile.json.
+//
+// sol-profiler always writes its output to coverage/coverage.json, so we move it out of the way
+// and restore any coverage report that was already there.
+async function writeGasProfile() {
+  if (!profilerSubprovider) {
+    throw new Error('GAS_PROFILE_ENABLED was not set when the bridge started');
+  }
+  const coveragePath = 'coverage/coverage.json';
+  const coverage = fs.existsSync(coveragePath) ? fs.readFileSync(coveragePath) : null;
+  await profilerSubprovider.writeProfilerOutputAsync();
+  const istanbul = JSON.parse(fs.readFileSync(coveragePath));
+  if (coverage) {
+    fs.writeFileSync(coveragePath, coverage);
+  } else {
+    fs.unlinkSync(coveragePath);
+  }
+  fs.mkdirSync('gas-profile', {recursive: true});
+  fs.writeFileSync('gas-profile/gas-profile.json', JSON.stringify(gasBySourceRange(istanbul), null, 2));
+  return true;
+}
+
 // rpcs contains implementations of all of the RPCs we support, keyed by
 // their "method name".
 const rpcs = {
@@ -57,6 +106,7 @@ const rpcs = {
 
   // Other RPCs.
   writeCoverage: _ => coverageSubprovider.writeCoverageAsync().then(_ => true),
+  writeGasProfile: _ => writeGasProfile(),
 	close: _ => {
     setImmediate(server.close.bind(server), (err, value) => {
       provider.stop();
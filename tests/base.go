@@ -3,6 +3,7 @@
 package tests
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
@@ -13,9 +14,12 @@ import (
 
 	"os/exec"
 	"reflect"
+	"strconv"
 	"strings"
+	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
@@ -23,9 +27,11 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/reserve-protocol/rsv-beta/abi"
+	"github.com/reserve-protocol/rsv-beta/ops"
 	"github.com/reserve-protocol/rsv-beta/soltools"
 )
 
@@ -45,6 +51,8 @@ type TestSuite struct {
 	node    interface {
 		bind.ContractBackend
 		TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+		BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+		StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
 	}
 	owner                  account
 	reserve                *abi.Reserve
@@ -63,16 +71,57 @@ type TestSuite struct {
 	proposalFactoryAddress common.Address
 
 	utilContract *bind.BoundContract
+	multicall    *abi.Multicall
 
 	logParsers map[common.Address]logParser
 
 	operator account
 	proposer account
 	weights  []*big.Int
+
+	// mineTimeout is how long to wait for each transaction to be mined. Zero means no limit.
+	mineTimeout time.Duration
+
+	// chainID is the ID of the chain the tests run on, which they set as Reserves' EIP-712
+	// chain ID.
+	chainID *big.Int
 }
 
 var coverageEnabled = os.Getenv("COVERAGE_ENABLED") != ""
 
+// remoteRPCURL, if set, runs the tests against a live node at that URL instead of a simulated
+// one; see createRemoteNode.
+var remoteRPCURL = os.Getenv("REMOTE_RPC_URL")
+
+// remoteMineTimeout is how long to wait for each transaction to be mined on a remote node, so a
+// stuck node fails the tests instead of hanging them. Set it with REMOTE_MINE_TIMEOUT, in
+// time.ParseDuration's format; it defaults to a minute.
+var remoteMineTimeout = func() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("REMOTE_MINE_TIMEOUT"))
+	if err != nil {
+		return time.Minute
+	}
+	return timeout
+}()
+
+// gasProfileEnabled turns on gas profiling, which, like coverage, runs through soltools.Backend.
+// The profile is written to gas-profile/gas-profile.json; see soltools.Backend.WriteGasProfile.
+var gasProfileEnabled = os.Getenv("GAS_PROFILE_ENABLED") != ""
+
+// runSuite runs the test suite `s` as part of the test `t`.
+//
+// Each suite deploys its contracts to its own in-process node, so suites don't share any state
+// and run in parallel with each other. With coverage or gas profiling enabled, though, all suites
+// share the one instrumented node that TestMain starts, so they run one at a time. Against a
+// remote node they also run one at a time, since every suite sends as the same account, and each
+// suite's ops.NonceManager only knows about its own transactions.
+func runSuite(t *testing.T, s suite.TestingSuite) {
+	if !coverageEnabled && !gasProfileEnabled && remoteRPCURL == "" {
+		t.Parallel()
+	}
+	suite.Run(t, s)
+}
+
 // requireTxWithStrictEvents(tx, err)(events...) requires that a transaction is successfully mined,
 // does not revert, and that err is nil. The result of requireTxWithStrictEvents takes a
 // variable-length list error arguments, and requires that exactly that set of events was thrown
@@ -92,14 +141,48 @@ func (s *TestSuite) requireTxWithStrictEvents(tx *types.Transaction, err error)
 	// return a closure that can take a varargs list of events,
 	// and assert that the transaction generates those events.
 	return func(assertEvent ...fmt.Stringer) {
-		if s.Equal(len(assertEvent), len(receipt.Logs), "did not get the expected number of events") {
+		ok := s.Equal(len(assertEvent), len(receipt.Logs), "did not get the expected number of events")
+		if ok {
 			for i, wantEvent := range assertEvent {
 				parser := s.logParsers[receipt.Logs[i].Address]
 				if s.NotNil(parser, "got an event from an unexpected contract address: "+receipt.Logs[i].Address.Hex()) {
 					gotEvent, err := parser.ParseLog(receipt.Logs[i])
 					if s.NoErrorf(err, "parsing event %v", i) {
-						s.Equal(wantEvent.String(), gotEvent.String())
+						ok = s.Equal(wantEvent.String(), gotEvent.String()) && ok
+					} else {
+						ok = false
 					}
+				} else {
+					ok = false
+				}
+			}
+		}
+		if !ok {
+			s.dumpEvents(receipt)
+		}
+	}
+}
+
+// requireTxWithKnownEvents(tx, err)(events...) is like requireTxWithStrictEvents, but ignores
+// events from contracts that have no parser in s.logParsers. It's for transactions that call out
+// to contracts the test doesn't track: the events from tracked contracts must be exactly
+// `events`, in order, and anything else is skipped.
+func (s *TestSuite) requireTxWithKnownEvents(tx *types.Transaction, err error) func(assertEvent ...fmt.Stringer) {
+	receipt := s._requireTxStatus(tx, err, types.ReceiptStatusSuccessful)
+
+	var known []*types.Log
+	for _, log := range receipt.Logs {
+		if s.logParsers[log.Address] != nil {
+			known = append(known, log)
+		}
+	}
+
+	return func(assertEvent ...fmt.Stringer) {
+		if s.Equal(len(assertEvent), len(known), "did not get the expected number of known events") {
+			for i, wantEvent := range assertEvent {
+				gotEvent, err := s.logParsers[known[i].Address].ParseLog(known[i])
+				if s.NoErrorf(err, "parsing event %v", i) {
+					s.Equal(wantEvent.String(), gotEvent.String())
 				}
 			}
 		}
@@ -141,6 +224,92 @@ func (s *TestSuite) requireTx(tx *types.Transaction, err error) func(assertEvent
 	}
 }
 
+// requireTxNoEvents requires that a transaction is successfully mined, does not revert, that err
+// is nil, and that it emitted no events at all.
+func (s *TestSuite) requireTxNoEvents(tx *types.Transaction, err error) {
+	receipt := s._requireTxStatus(tx, err, types.ReceiptStatusSuccessful)
+	s.Empty(s.describeEvents(receipt.Logs), "expected no events")
+}
+
+// dumpEvents logs each event in `receipt`, as described by describeEvents, to help debug
+// failing event assertions. The log is only shown for failing tests, or with `go test -v`.
+func (s *TestSuite) dumpEvents(receipt *types.Receipt) {
+	s.T().Logf("transaction %v emitted %v events:", receipt.TxHash.Hex(), len(receipt.Logs))
+	for i, event := range s.describeEvents(receipt.Logs) {
+		s.T().Logf("  %v: %v", i, event)
+	}
+}
+
+// describeEvents returns a description of each of `logs`: the parsed event if one of
+// s.logParsers can parse it, or its address and topics if not.
+func (s *TestSuite) describeEvents(logs []*types.Log) []string {
+	var events []string
+	for _, log := range logs {
+		// common.Hash formats as raw bytes with %v, so spell out the topics in hex.
+		topics := make([]string, len(log.Topics))
+		for i, topic := range log.Topics {
+			topics[i] = topic.Hex()
+		}
+		description := fmt.Sprintf("event at %v with topics %v", log.Address.Hex(), topics)
+		if parser := s.logParsers[log.Address]; parser != nil {
+			if event, err := parser.ParseLog(log); err == nil {
+				description = event.String()
+			}
+		}
+		events = append(events, description)
+	}
+	return events
+}
+
+// requireEventField requires that `receipt` contains an event of the same type as `eventType`,
+// such as abi.ReserveTransfer{}, and that the field `fieldName` of the first such event equals
+// `expected`. Unlike the event assertions in requireTx and requireTxWithStrictEvents, which
+// compare whole events as strings, a mismatch names the field that differs.
+func (s *TestSuite) requireEventField(
+	receipt *types.Receipt, eventType interface{}, fieldName string, expected interface{},
+) {
+	wantType := reflect.TypeOf(eventType)
+	for _, log := range receipt.Logs {
+		parser := s.logParsers[log.Address]
+		if parser == nil {
+			continue
+		}
+		event, err := parser.ParseLog(log)
+		if err != nil || reflect.TypeOf(event).Elem() != wantType {
+			continue
+		}
+		if mismatch, ok := eventFieldMismatch(event, fieldName, expected); !ok {
+			s.Require().FailNow(mismatch)
+		}
+		return
+	}
+	s.Require().FailNowf("event not found", "no %v event in receipt", wantType.Name())
+}
+
+// eventFieldMismatch compares the field `fieldName` of `event`, a parsed event or a pointer to
+// one, to `expected`. If they differ, or the event has no such field, it returns a description
+// of the difference and false. *big.Int fields are compared by value.
+func eventFieldMismatch(event interface{}, fieldName string, expected interface{}) (string, bool) {
+	value := reflect.Indirect(reflect.ValueOf(event))
+	name := value.Type().Name()
+	field := value.FieldByName(fieldName)
+	if !field.IsValid() {
+		return fmt.Sprintf("%v has no field %v", name, fieldName), false
+	}
+
+	got := field.Interface()
+	equal := reflect.DeepEqual(expected, got)
+	if want, ok := expected.(*big.Int); ok {
+		if got, ok := got.(*big.Int); ok && want != nil && got != nil {
+			equal = want.Cmp(got) == 0
+		}
+	}
+	if !equal {
+		return fmt.Sprintf("%v.%v: expected %v, got %v", name, fieldName, expected, got), false
+	}
+	return "", true
+}
+
 // requireTxFails is like requireTxWithEvents, but it requires that the transaction either
 // reverts or is not successfully made in the first place due to gas estimation
 // failing.
@@ -154,15 +323,146 @@ func (s *TestSuite) requireTxFails(tx *types.Transaction, err error) {
 	s.Equal(0, len(receipt.Logs), "Zero logs should be generated for a failed transaction")
 }
 
+// requireTxRevertsWith(tx, err)(reason) is like requireTxFails, but it also requires that the
+// transaction reverted with the revert reason `reason`. Like requireTxWithStrictEvents, it
+// returns a closure so that it can directly wrap our abigen'd mutator calls.
+//
+// A transaction whose gas estimate fails is never sent, and so has no revert reason to inspect.
+// So the transaction must be sent with a fixed gas limit (see `withGasLimit`), to ensure that it
+// is mined. Its revert reason is then found by replaying it as a call against the current state;
+// since a reverted transaction changes no contract state, this reproduces the original failure.
+func (s *TestSuite) requireTxRevertsWith(tx *types.Transaction, err error) func(reason string) {
+	receipt := s._requireTxStatus(tx, err, types.ReceiptStatusFailed)
+	s.Equal(0, len(receipt.Logs), "Zero logs should be generated for a failed transaction")
+
+	var txSigner types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		txSigner = types.NewEIP155Signer(tx.ChainId())
+	}
+	from, err := types.Sender(txSigner, tx)
+	s.Require().NoError(err)
+	result, err := s.node.CallContract(context.Background(), ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}, nil)
+	s.Require().NoError(err)
+
+	return func(reason string) {
+		gotReason, ok := ops.RevertReason(result)
+		if s.True(ok, "transaction reverted without a reason") {
+			s.Equal(reason, gotReason)
+		}
+	}
+}
+
+// requireNoDuplicateEvents requires that no two of the events in `receipt` are identical: from
+// the same contract, with the same topics and data. That usually indicates a bug that emits an
+// event twice.
+func (s *TestSuite) requireNoDuplicateEvents(receipt *types.Receipt) {
+	if i, j, found := duplicateLogs(receipt.Logs); found {
+		event := fmt.Sprint(receipt.Logs[i].Topics)
+		if parser := s.logParsers[receipt.Logs[i].Address]; parser != nil {
+			if parsed, err := parser.ParseLog(receipt.Logs[i]); err == nil {
+				event = parsed.String()
+			}
+		}
+		s.Require().FailNowf("duplicate events", "events %v and %v are both %v", i, j, event)
+	}
+}
+
+// duplicateLogs finds the first pair of identical logs in `logs`, if there is one.
+func duplicateLogs(logs []*types.Log) (i, j int, found bool) {
+	for i = range logs {
+		for j = i + 1; j < len(logs); j++ {
+			if logs[i].Address == logs[j].Address &&
+				reflect.DeepEqual(logs[i].Topics, logs[j].Topics) &&
+				bytes.Equal(logs[i].Data, logs[j].Data) {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// receipt returns the receipt of the mined transaction `tx`.
+func (s *TestSuite) receipt(tx *types.Transaction) *types.Receipt {
+	receipt, err := s.node.TransactionReceipt(context.Background(), tx.Hash())
+	s.Require().NoError(err)
+	return receipt
+}
+
+// coverageAttempts is how many attempts _requireTxStatus makes, through waitMinedWithRetry, to
+// see a transaction mined on the coverage node.
+const coverageAttempts = 3
+
+// waitMinedWithRetry waits for the already-sent transaction `tx` to be mined. Against the
+// coverage node, where transactions are occasionally slow to be mined, it waits up to `attempts`
+// times, backing off exponentially between them. Against the fast in-process node it waits just
+// once.
+//
+// It only ever waits; it never re-sends `tx`. A failure to send shows up as the error returned
+// by the binding call, which callers check before waiting.
+func (s *TestSuite) waitMinedWithRetry(tx *types.Transaction, attempts int) error {
+	timeout := s.mineTimeout
+	if coverageEnabled {
+		timeout = 10 * time.Second
+	} else {
+		attempts = 1
+	}
+
+	var err error
+	backoff := 100 * time.Millisecond
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if _, err = waitMined(s.node, tx, timeout); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
 func (s *TestSuite) _requireTxStatus(tx *types.Transaction, err error, status uint64) *types.Receipt {
 	s.Require().NoError(err)
 	s.Require().NotNil(tx)
-	receipt, err := bind.WaitMined(context.Background(), s.node, tx)
-	s.Require().NoError(err)
+	s.Require().NoError(s.waitMinedWithRetry(tx, coverageAttempts))
+	receipt := s.receipt(tx)
 	s.Require().Equal(status, receipt.Status)
 	return receipt
 }
 
+// waitMined waits for `tx` to be mined on `b`, and returns its receipt. If `timeout` is nonzero
+// and passes first, it returns an error saying so.
+func waitMined(b bind.DeployBackend, tx *types.Transaction, timeout time.Duration) (*types.Receipt, error) {
+	if timeout == 0 {
+		return bind.WaitMined(context.Background(), b, tx)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, b, tx)
+	if err == context.DeadlineExceeded {
+		return nil, fmt.Errorf("transaction %v was not mined within %v", tx.Hash().Hex(), timeout)
+	}
+	return receipt, err
+}
+
+// assertGasUnder requires that `tx` is mined successfully, and asserts that it used no more than
+// `limit` gas. Under coverage, contracts are instrumented and use more gas, so only the first
+// requirement is checked.
+func (s *TestSuite) assertGasUnder(tx *types.Transaction, limit uint64) {
+	receipt := s._requireTxStatus(tx, nil, types.ReceiptStatusSuccessful)
+	if coverageEnabled {
+		return
+	}
+	s.Truef(receipt.GasUsed <= limit, "transaction used %v gas, over the limit of %v", receipt.GasUsed, limit)
+}
+
 // assertRSVBalance asserts that the Reserve Dollar balance of `address` is `amount`.
 func (s *TestSuite) assertRSVBalance(address common.Address, amount *big.Int) {
 	balance, err := s.reserve.BalanceOf(nil, address)
@@ -170,6 +470,20 @@ func (s *TestSuite) assertRSVBalance(address common.Address, amount *big.Int) {
 	s.Equal(amount.String(), balance.String()) // assert.Equal can mis-compare big.Ints, so compare strings instead
 }
 
+// captureBalance returns the current Reserve Dollar balance of `address`, to pass to
+// assertBalanceDelta after acting.
+func (s *TestSuite) captureBalance(address common.Address) *big.Int {
+	balance, err := s.reserve.BalanceOf(nil, address)
+	s.Require().NoError(err)
+	return balance
+}
+
+// assertBalanceDelta asserts that the Reserve Dollar balance of `address` is now `before` plus
+// `delta`. `delta` is negative for a balance that decreased.
+func (s *TestSuite) assertBalanceDelta(address common.Address, before *big.Int, delta *big.Int) {
+	s.assertRSVBalance(address, new(big.Int).Add(before, delta))
+}
+
 // assertRSVAllowance asserts that the allowance of Reserve Dollars that `owner` has given `spender` is `amount`.
 func (s *TestSuite) assertRSVAllowance(owner, spender common.Address, amount *big.Int) {
 	allowance, err := s.reserve.Allowance(nil, owner, spender)
@@ -184,6 +498,103 @@ func (s *TestSuite) assertRSVTotalSupply(amount *big.Int) {
 	s.Equal(amount.String(), totalSupply.String())
 }
 
+// pureViewSlots is how many leading storage slots of each contract assertPureView compares.
+// That's enough for every state variable our contracts declare directly. Mapping and dynamic
+// array entries live at hashed slots, and aren't compared.
+const pureViewSlots = 32
+
+// storageSlots returns the first `count` storage slots of the contract at `address`.
+func (s *TestSuite) storageSlots(address common.Address, count int) []common.Hash {
+	slots := make([]common.Hash, count)
+	for i := range slots {
+		value, err := s.node.StorageAt(context.Background(), address, common.BigToHash(big.NewInt(int64(i))), nil)
+		s.Require().NoError(err)
+		slots[i] = common.BytesToHash(value)
+	}
+	return slots
+}
+
+// assertPureView runs `call`, which should only read chain state, and asserts that it changed
+// none of the first pureViewSlots storage slots of each contract in `contracts`. It also asserts
+// that no block was mined and no account's nonce changed, which catches a "read" that is really
+// a transaction even if it only writes mapping entries.
+func (s *TestSuite) assertPureView(call func(), contracts ...common.Address) {
+	nonces := func() []uint64 {
+		result := make([]uint64, len(s.account))
+		for i, account := range s.account {
+			nonce, err := s.node.PendingNonceAt(context.Background(), account.address())
+			s.Require().NoError(err)
+			result[i] = nonce
+		}
+		return result
+	}
+	storage := func() map[common.Address][]common.Hash {
+		result := make(map[common.Address][]common.Hash)
+		for _, contract := range contracts {
+			result[contract] = s.storageSlots(contract, pureViewSlots)
+		}
+		return result
+	}
+
+	blockBefore := s.currentBlockNumber()
+	noncesBefore := nonces()
+	storageBefore := storage()
+	call()
+	s.Equal(blockBefore.String(), s.currentBlockNumber().String(), "a block was mined")
+	s.Equal(noncesBefore, nonces(), "a transaction was sent")
+	s.Equal(storageBefore, storage(), "contract storage changed")
+}
+
+// deployedCodeHash returns the keccak256 hash of the code deployed at `address`.
+func (s *TestSuite) deployedCodeHash(address common.Address) common.Hash {
+	code, err := s.node.CodeAt(context.Background(), address, nil)
+	s.Require().NoError(err)
+	s.Require().NotEmptyf(code, "no code deployed at %v", address.Hex())
+	return crypto.Keccak256Hash(code)
+}
+
+// assertDeployedCodeHash asserts that the keccak256 hash of the code deployed at `address` is
+// `expected`, for example to check a deployment against an audited build.
+func (s *TestSuite) assertDeployedCodeHash(address common.Address, expected common.Hash) {
+	s.Equal(expected.Hex(), s.deployedCodeHash(address).Hex())
+}
+
+// assertRoles asserts that the Reserve's minter, pauser, and fee recipient are as given.
+func (s *TestSuite) assertRoles(minter, pauser, feeRecipient common.Address) {
+	foundMinter, err := s.reserve.Minter(nil)
+	s.Require().NoError(err)
+	s.Equal(minter, foundMinter, "minter")
+
+	foundPauser, err := s.reserve.Pauser(nil)
+	s.Require().NoError(err)
+	s.Equal(pauser, foundPauser, "pauser")
+
+	foundFeeRecipient, err := s.reserve.FeeRecipient(nil)
+	s.Require().NoError(err)
+	s.Equal(feeRecipient, foundFeeRecipient, "feeRecipient")
+}
+
+// assertERC20Allowance asserts that the allowance of `erc20` tokens that `owner` has given `spender` is `amount`.
+func (s *TestSuite) assertERC20Allowance(erc20 *abi.BasicERC20, owner, spender common.Address, amount *big.Int) {
+	allowance, err := erc20.Allowance(nil, owner, spender)
+	s.NoError(err)
+	s.Equal(amount.String(), allowance.String())
+}
+
+// assertERC20Balance asserts that the balance of `erc20` tokens held by `address` is `amount`.
+func (s *TestSuite) assertERC20Balance(erc20 *abi.BasicERC20, address common.Address, amount *big.Int) {
+	balance, err := erc20.BalanceOf(nil, address)
+	s.NoError(err)
+	s.Equal(amount.String(), balance.String())
+}
+
+// assertERC20TotalSupply asserts that the total supply of `erc20` tokens is `amount`.
+func (s *TestSuite) assertERC20TotalSupply(erc20 *abi.BasicERC20, amount *big.Int) {
+	totalSupply, err := erc20.TotalSupply(nil)
+	s.NoError(err)
+	s.Equal(amount.String(), totalSupply.String())
+}
+
 // assertManagerCollateralized asserts that the Manager is collateralized.
 func (s *TestSuite) assertManagerCollateralized() {
 	collateralized, err := s.manager.IsFullyCollateralized(nil)
@@ -191,6 +602,13 @@ func (s *TestSuite) assertManagerCollateralized() {
 	s.True(collateralized)
 }
 
+// assertManagerUndercollateralized asserts that the Manager is not fully collateralized.
+func (s *TestSuite) assertManagerUndercollateralized() {
+	collateralized, err := s.manager.IsFullyCollateralized(nil)
+	s.Require().NoError(err)
+	s.False(collateralized)
+}
+
 // assertBasket asserts that the current manager basket matches expectations.
 func (s *TestSuite) assertBasket(basket *abi.Basket, tokens []common.Address, weights []*big.Int) {
 	// Get tokens
@@ -207,26 +625,164 @@ func (s *TestSuite) assertBasket(basket *abi.Basket, tokens []common.Address, we
 	}
 }
 
+// assertBasketContains asserts that s.basket contains `token` with weight `weight`, and that
+// indexOf finds it at its place in the basket's tokens.
+func (s *TestSuite) assertBasketContains(token common.Address, weight *big.Int) {
+	has, err := s.basket.Has(nil, token)
+	s.Require().NoError(err)
+	s.Require().True(has, "basket does not contain %v", token.Hex())
+
+	index, err := s.basket.IndexOf(nil, token)
+	s.Require().NoError(err)
+	found, err := s.basket.Tokens(nil, index)
+	s.Require().NoError(err)
+	s.Equal(token, found)
+
+	foundWeight, err := s.basket.Weights(nil, token)
+	s.Require().NoError(err)
+	s.Equal(weight.String(), foundWeight.String())
+}
+
+// assertBasketWeightsSum asserts that the weights of `basket` sum to `expected`.
+func (s *TestSuite) assertBasketWeightsSum(basket *abi.Basket, expected *big.Int) {
+	sum, err := basket.WeightsSum(nil)
+	s.Require().NoError(err)
+	s.Equal(expected.String(), sum.String())
+}
+
+// assertVaultCollateralComposition asserts that, for each token in s.basket, the Vault holds the
+// amount of that token that backs the current RSV supply at the token's basket weight. Transfers
+// into the Vault round up, so each balance may exceed its exact share by a few qTokens.
+func (s *TestSuite) assertVaultCollateralComposition() {
+	supply, err := s.reserve.TotalSupply(nil)
+	s.Require().NoError(err)
+	decimals, err := s.reserve.Decimals(nil)
+	s.Require().NoError(err)
+	// Weights are in aqTokens per RSV, as in Manager._weighted.
+	scaleFactor := shiftLeft(1, 18+uint32(decimals))
+	// One qToken of rounding per issuance or basket shift is plenty for these tests.
+	const slack = 2
+
+	tokens, err := s.basket.GetTokens(nil)
+	s.Require().NoError(err)
+	for _, token := range tokens {
+		weight, err := s.basket.Weights(nil, token)
+		s.Require().NoError(err)
+		expected := bigInt(0).Mul(supply, weight)
+		expected.Div(expected, scaleFactor)
+
+		erc20, err := abi.NewBasicERC20(token, s.node)
+		s.Require().NoError(err)
+		balance, err := erc20.BalanceOf(nil, s.vaultAddress)
+		s.Require().NoError(err)
+
+		excess := bigInt(0).Sub(balance, expected)
+		s.True(excess.Sign() >= 0 && excess.Cmp(bigInt(slack)) <= 0,
+			"vault holds %v of %v, want %v (plus up to %v)",
+			balance, token.Hex(), expected, slack,
+		)
+	}
+}
+
+// collectTransfers returns every Transfer event that s.reserve emitted in the blocks from
+// `fromBlock` through `toBlock`, in the order they were emitted.
+func (s *TestSuite) collectTransfers(fromBlock, toBlock uint64) []abi.ReserveTransfer {
+	iter, err := s.reserve.FilterTransfer(
+		&bind.FilterOpts{Start: fromBlock, End: &toBlock}, nil, nil,
+	)
+	s.Require().NoError(err)
+	defer iter.Close()
+
+	var transfers []abi.ReserveTransfer
+	for iter.Next() {
+		transfers = append(transfers, *iter.Event)
+	}
+	s.Require().NoError(iter.Error())
+	return transfers
+}
+
 // currentTimestamp retrieves the current block time.
 func (s *TestSuite) currentTimestamp() *big.Int {
 	result := new(big.Int)
-	s.NoError(s.utilContract.Call(nil, &result, "time"))
+	s.callView(s.utilContract, &result, "time")
 	return result
 }
 
-// createSlowCoverageNode creates a connection to a local geth node that passes through
-// sol-coverage instrumentation. This mode is significantly slower than running against
-// the in-process node created by `createFastNode`.
-//
-// This connection is then available as `s.node`.
-func (s *TestSuite) createSlowCoverageNode() {
+// currentBlockNumber retrieves the current block number.
+func (s *TestSuite) currentBlockNumber() *big.Int {
+	result := new(big.Int)
+	s.callView(s.utilContract, &result, "blockNumber")
+	return result
+}
+
+// batchedCall is one call for batchCall to make: `Data` is the ABI-encoded call to `Target`.
+type batchedCall struct {
+	Target common.Address
+	Data   []byte
+}
+
+// batchCall makes all of `calls` in a single eth_call through s.multicall, and returns their
+// raw results in order. It requires that every call succeeds.
+func (s *TestSuite) batchCall(calls []batchedCall) [][]byte {
+	targets := make([]common.Address, len(calls))
+	data := make([][]byte, len(calls))
+	for i, call := range calls {
+		targets[i], data[i] = call.Target, call.Data
+	}
+	results, err := s.multicall.Aggregate(nil, targets, data)
+	s.Require().NoError(err)
+	s.Require().Len(results, len(calls))
+	return results
+}
+
+// batchBalances returns the Reserve Dollar balances of `addresses`, read in one batch.
+func (s *TestSuite) batchBalances(addresses []common.Address) []*big.Int {
+	reserveABI, err := ethabi.JSON(strings.NewReader(abi.ReserveABI))
+	s.Require().NoError(err)
+
+	calls := make([]batchedCall, len(addresses))
+	for i, address := range addresses {
+		data, err := reserveABI.Pack("balanceOf", address)
+		s.Require().NoError(err)
+		calls[i] = batchedCall{Target: s.reserveAddress, Data: data}
+	}
+
+	balances := make([]*big.Int, len(addresses))
+	for i, result := range s.batchCall(calls) {
+		balance := new(big.Int)
+		s.Require().NoError(reserveABI.Unpack(&balance, "balanceOf", result))
+		balances[i] = balance
+	}
+	return balances
+}
+
+// callView calls the view function `method` on `contract` with `args`, and unpacks its return
+// value into `result`. It requires that the call succeeds.
+func (s *TestSuite) callView(contract *bind.BoundContract, result interface{}, method string, args ...interface{}) {
+	s.Require().NoErrorf(contract.Call(nil, result, method, args...), "calling %v", method)
+}
+
+// coverageNode is the instrumented node that every suite shares when coverage or gas profiling
+// is enabled. TestMain starts it before any suite runs and stops it after they all finish, so
+// that a single bridge process collects the coverage and gas data for the whole run.
+var coverageNode *soltools.Backend
+
+// startCoverageNode sets coverageNode to a connection to a local geth node that passes through
+// sol-coverage instrumentation.
+func startCoverageNode() error {
 	fmt.Fprintln(os.Stderr, "\nA local geth node must be running for coverage to work.")
 	fmt.Fprintln(os.Stderr, "If one is not already running, start one in a new terminal with:")
 	fmt.Fprintln(os.Stderr, "\n\tmake run-geth")
 
-	var err error
-	s.node, err = soltools.NewBackend("http://localhost:8545")
-	s.Require().NoError(err)
+	node, err := soltools.NewBackend("http://localhost:8545")
+	if err != nil {
+		return err
+	}
+	key, err := crypto.HexToECDSA(accountKeys[0])
+	if err != nil {
+		node.Close()
+		return err
+	}
 
 	// Throwaway initial transaction.
 	// The tests fail if running against a newly-initialized 0xorg/devnet container.
@@ -245,13 +801,160 @@ func (s *TestSuite) createSlowCoverageNode() {
 	tx, _ := types.SignTx(
 		types.NewTransaction(0, common.Address{100}, bigInt(0), 21000, bigInt(1), nil),
 		types.HomesteadSigner{},
-		s.account[0].key,
+		key,
 	)
-	s.node.SendTransaction(context.Background(), tx)
+	node.SendTransaction(context.Background(), tx)
+
+	coverageNode = node
+	return nil
+}
+
+// stopCoverageNode closes coverageNode. With coverage enabled, it then processes the coverage
+// profile, which by now covers every suite, into an HTML report.
+func stopCoverageNode() error {
+	if err := coverageNode.Close(); err != nil {
+		return err
+	}
+	if coverageEnabled {
+		if out, err := exec.Command("npx", "istanbul", "report", "html").CombinedOutput(); err != nil {
+			fmt.Println()
+			fmt.Println("I generated coverage information in coverage/coverage.json.")
+			fmt.Println("I tried to process it with `istanbul` to turn it into a readable report, but failed.")
+			fmt.Println("The error I got when running istanbul was:", err)
+			fmt.Println("Istanbul's output was:\n" + string(out))
+		}
+	}
+	return nil
+}
+
+// createSlowCoverageNode makes the shared coverageNode available as `s.node`. This mode is
+// significantly slower than running against the in-process node created by `createFastNode`.
+func (s *TestSuite) createSlowCoverageNode() {
+	s.Require().NotNil(coverageNode, "the coverage node is started by TestMain")
+	s.node = coverageNode
+}
+
+// createRemoteNode creates a connection to a live Ethereum node at `rpcURL`, such as a testnet
+// RPC endpoint, for final validation before deploying there. It is then available as `s.node`.
+//
+// The owner account is replaced by the funded account whose hex private key is in the
+// REMOTE_PRIVATE_KEY env var, and s.signer signs for it with EIP-155 replay protection for
+// `chainID`. s.signer takes its nonces from an ops.NonceManager, so that transactions sent in
+// quick succession don't collide. The other accounts are not funded, so only tests that transact
+// solely as the owner can pass. Tests that need to control the clock are skipped; see adjustTime.
+func (s *TestSuite) createRemoteNode(rpcURL string, chainID *big.Int) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(os.Getenv("REMOTE_PRIVATE_KEY"), "0x"))
+	s.Require().NoError(err, "REMOTE_PRIVATE_KEY must be set to the private key of a funded account")
+
+	client, err := ethclient.Dial(rpcURL)
+	s.Require().NoError(err)
+	networkID, err := client.NetworkID(context.Background())
+	s.Require().NoError(err)
+	s.Require().Equal(chainID.String(), networkID.String(), "node at %v is on another chain", rpcURL)
+	s.node = client
+
+	s.account[0] = account{key: key}
+	s.owner = s.account[0]
+	keyAddress := s.owner.address()
+	s.signer = &bind.TransactOpts{
+		From: keyAddress,
+		Signer: func(_ types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != keyAddress {
+				return nil, fmt.Errorf("can't sign for %v", address.Hex())
+			}
+			return types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+		},
+	}
+	s.signer = ops.NewNonceManager(client, keyAddress).Wrap(s.signer)
+	s.mineTimeout = remoteMineTimeout
+}
+
+// isSimulated reports whether the tests are running on a local, simulated chain -- the in-process
+// node or the coverage node -- rather than a live remote node.
+func (s *TestSuite) isSimulated() bool {
+	switch s.node.(type) {
+	case backend, *soltools.Backend:
+		return true
+	}
+	return false
+}
+
+// adjustTime advances the simulated node's clock by `delta`. A remote node's clock can't be
+// controlled, so against one, adjustTime skips the current test.
+func (s *TestSuite) adjustTime(delta time.Duration) {
+	node, ok := s.node.(backend)
+	if !ok {
+		s.T().Skip("can't adjust the clock of a remote node")
+	}
+	s.Require().NoError(node.AdjustTime(delta))
+}
+
+// setBlockTime sets the simulated node's clock so that the next transaction is mined in a block
+// with timestamp `t`. The simulated node spaces blocks 10 seconds apart, and mines a block to move
+// its clock, so `t` must be at least 20 seconds after the latest block. Like adjustTime, it skips
+// the current test against a remote node.
+func (s *TestSuite) setBlockTime(t time.Time) {
+	node, ok := s.node.(backend)
+	if !ok {
+		s.T().Skip("can't set the clock of a remote node")
+	}
+	latest := time.Unix(s.currentTimestamp().Int64(), 0)
+	delta := t.Sub(latest) - 20*time.Second
+	s.Require().Truef(delta >= 0, "can't set block time to %v, less than 20s after %v", t, latest)
+	s.Require().NoError(node.AdjustTime(delta))
+}
+
+// advanceBlocks mines `n` empty blocks on the simulated node. Other nodes mine on their own
+// schedule, so against them, advanceBlocks only logs a warning.
+func (s *TestSuite) advanceBlocks(n int) {
+	node, ok := s.node.(backend)
+	if !ok {
+		s.T().Logf("warning: can't mine blocks on demand on this node; not advancing %v blocks", n)
+		return
+	}
+	for i := 0; i < n; i++ {
+		node.Commit()
+	}
+}
+
+// commitEmptyBlock mines one block, with no transactions in it, on the simulated node. Like
+// advanceBlocks, it only logs a warning against other nodes.
+func (s *TestSuite) commitEmptyBlock() {
+	s.advanceBlocks(1)
+}
+
+// advanceTimeAndBlocks advances the simulated node's clock by `delta` and mines `n` blocks.
+// AdjustTime mines a block itself, so that block counts toward `n`. Like advanceBlocks, it only
+// logs a warning against other nodes.
+func (s *TestSuite) advanceTimeAndBlocks(delta time.Duration, n int) {
+	node, ok := s.node.(backend)
+	if !ok {
+		s.T().Logf("warning: can't control this node; not advancing %v and %v blocks", delta, n)
+		return
+	}
+	if n < 1 {
+		n = 1
+	}
+	s.Require().NoError(node.AdjustTime(delta))
+	s.advanceBlocks(n - 1)
 }
 
 // createFastNode creates a fast in-process Ethereum node. It is then available as `s.node`.
 func (s *TestSuite) createFastNode() {
+	// Block gas limit. Needs to be more than 7e6, which is about the cost
+	// of the ReserveV2 constructor. But we still want it about the
+	// same order of magnitude as mainnet.
+	//
+	// The Reserve constructor is edging close to the mainnet block limit.
+	// We'll probably stay under it without any problem. If not, we can split
+	// the Eternal Storage contract deployment into a different transaction.
+	s.createFastNodeWithGasLimit(8e6)
+}
+
+// createFastNodeWithGasLimit is like createFastNode, but the node's blocks have a gas limit of
+// `gasLimit`. Tests that deploy contracts too large for the default limit can use it to replace
+// `s.node`.
+func (s *TestSuite) createFastNodeWithGasLimit(gasLimit uint64) {
 	genesisAlloc := core.GenesisAlloc{}
 	for _, account := range s.account {
 		genesisAlloc[account.address()] = core.GenesisAccount{
@@ -259,48 +962,56 @@ func (s *TestSuite) createFastNode() {
 		}
 	}
 	s.node = backend{
-		backends.NewSimulatedBackend(
-			genesisAlloc,
-			// Block gas limit. Needs to be more than 7e6, which is about the cost
-			// of the ReserveV2 constructor. But we still want it about the
-			// same order of magnitude as mainnet.
-			//
-			// The Reserve constructor is edging close to the mainnet block limit.
-			// We'll probably stay under it without any problem. If not, we can split
-			// the Eternal Storage contract deployment into a different transaction.
-			8e6,
-		),
+		backends.NewSimulatedBackend(genesisAlloc, gasLimit),
 	}
 }
 
+// accountKeys are the private keys of the test accounts: the first few keys from the following
+// well-known mnemonic used by 0x:
+//	concert load couple harbor equip island argue ramp clarify fence smart topic
+var accountKeys = []string{
+	"f2f48ee19680706196e2e339e5da3491186e0c4c5030670656b0e0164837257d",
+	"5d862464fe9303452126c8bc94274b8c5f9874cbd219789b3eb2128075a76f72",
+	"df02719c4df8b9b8ac7f551fcb5d9ef48fa27eef7a66453879f4d8fdc6e78fb1",
+	"ff12e391b79415e941a94de3bf3a9aee577aed0731e297d5cfa0b8a1e02fa1d0",
+	"752dd9cf65e68cfaba7d60225cbdbc1f4729dd5e5507def72815ed0d8abc6249",
+	"efb595a0178eb79a8df953f87c5148402a224cdf725e88c0146727c6aceadccd",
+}
+
 // setup sets up the TestSuite. It must be called before using s.account or s.signer.
 func (s *TestSuite) setup() {
-	// The first few keys from the following well-known mnemonic used by 0x:
-	//	concert load couple harbor equip island argue ramp clarify fence smart topic
-	keys := []string{
-		"f2f48ee19680706196e2e339e5da3491186e0c4c5030670656b0e0164837257d",
-		"5d862464fe9303452126c8bc94274b8c5f9874cbd219789b3eb2128075a76f72",
-		"df02719c4df8b9b8ac7f551fcb5d9ef48fa27eef7a66453879f4d8fdc6e78fb1",
-		"ff12e391b79415e941a94de3bf3a9aee577aed0731e297d5cfa0b8a1e02fa1d0",
-		"752dd9cf65e68cfaba7d60225cbdbc1f4729dd5e5507def72815ed0d8abc6249",
-		"efb595a0178eb79a8df953f87c5148402a224cdf725e88c0146727c6aceadccd",
-	}
-	s.account = make([]account, len(keys))
-	for i, key := range keys {
-		b, err := hex.DecodeString(key)
-		s.Require().NoError(err)
-		s.account[i].key, err = crypto.ToECDSA(b)
+	s.account = make([]account, len(accountKeys))
+	for i, key := range accountKeys {
+		var err error
+		s.account[i].key, err = crypto.HexToECDSA(key)
 		s.Require().NoError(err)
 	}
 	s.signer = signer(s.account[0])
 	s.owner = s.account[0]
 
-	s.createFastNode()
+	// The simulated nodes' chain ID.
+	s.chainID = big.NewInt(1337)
+	switch {
+	case remoteRPCURL != "":
+		chainID, ok := new(big.Int).SetString(os.Getenv("REMOTE_CHAIN_ID"), 10)
+		s.Require().True(ok, "REMOTE_CHAIN_ID must be set to the remote chain's ID")
+		s.chainID = chainID
+		s.createRemoteNode(remoteRPCURL, chainID)
+	case coverageEnabled || gasProfileEnabled:
+		s.createSlowCoverageNode()
+	default:
+		s.createFastNode()
+	}
 
-	// Deploy utility contract just for reading block time
-	bytecode := "0x6080604052348015600f57600080fd5b5060918061001e6000396000f3fe6080604052348015600f57600080fd5b50600436106044577c0100000000000000000000000000000000000000000000000000000000600035046316ada54781146049575b600080fd5b604f6061565b60408051918252519081900360200190f35b429056fea165627a7a723058205524d6a0c4d80ea5535c2ea64615c2619a21518e242cb929275cbd678b04468f0029"
+	// Deploy utility contract just for reading block time and number. This bytecode is hand-assembled;
+	// it implements the following two functions, and reverts on any other call:
+	//
+	//	function time() external view returns(uint256) { return now; }
+	//	function blockNumber() external view returns(uint256) { return block.number; }
+	bytecode := "0x604a80600b6000396000f36000357c01000000000000000000000000000000000000000000000000000000009004806316ada54714603a576357e871e714603f57600080fd5b426041565b435b60005260206000f3"
 	utilABI, err := ethabi.JSON(strings.NewReader(`
-	[{"constant":true,"inputs":[],"name":"time","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]
+	[{"constant":true,"inputs":[],"name":"time","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},
+	{"constant":true,"inputs":[],"name":"blockNumber","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]
 	`))
 	s.Require().NoError(err)
 
@@ -308,27 +1019,26 @@ func (s *TestSuite) setup() {
 	s.Require().NoError(err)
 
 	_, tx, utilContract, err := bind.DeployContract(s.signer, utilABI, code, s.node)
-	s.requireTx(tx, err)( /* assert zero events */ )
+	s.requireTxNoEvents(tx, err)
 	s.utilContract = utilContract
+
+	// Deploy Multicall, for batching reads; see batchCall.
+	_, tx, multicall, err := abi.DeployMulticall(s.signer, s.node)
+	s.requireTxNoEvents(tx, err)
+	s.multicall = multicall
 }
 
-// TearDownSuite runs once, after all of the tests in the suite.
+// TearDownSuite runs once, after all of the tests in the suite. It writes the coverage and gas
+// profiles collected so far by the shared coverage node, so the last suite's write covers every
+// suite.
 func (s *TestSuite) TearDownSuite() {
 	if coverageEnabled {
 		// Write coverage profile to disk.
-		s.Assert().NoError(s.node.(*soltools.Backend).WriteCoverage())
-
-		// Close the node.js process.
-		s.Assert().NoError(s.node.(*soltools.Backend).Close())
-
-		// Process coverage profile into an HTML report.
-		if out, err := exec.Command("npx", "istanbul", "report", "html").CombinedOutput(); err != nil {
-			fmt.Println()
-			fmt.Println("I generated coverage information in coverage/coverage.json.")
-			fmt.Println("I tried to process it with `istanbul` to turn it into a readable report, but failed.")
-			fmt.Println("The error I got when running istanbul was:", err)
-			fmt.Println("Istanbul's output was:\n" + string(out))
-		}
+		s.Assert().NoError(coverageNode.WriteCoverage())
+	}
+	if gasProfileEnabled {
+		// Write gas profile to disk.
+		s.Assert().NoError(coverageNode.WriteGasProfile())
 	}
 }
 
@@ -355,6 +1065,15 @@ func (b backend) AdjustTime(delta time.Duration) error {
 	return b.SimulatedBackend.AdjustTime(delta)
 }
 
+// withGasLimit returns a copy of `opts` that sends transactions with a fixed gas limit,
+// instead of estimating it. Failing transactions sent this way are mined, rather than being
+// rejected before they are sent.
+func withGasLimit(opts *bind.TransactOpts, gasLimit uint64) *bind.TransactOpts {
+	result := *opts
+	result.GasLimit = gasLimit
+	return &result
+}
+
 // signer returns a *bind.TransactOpts that uses a's private key to sign transactions.
 func signer(a account) *bind.TransactOpts {
 	return bind.NewKeyedTransactor(a.key)
@@ -416,12 +1135,92 @@ func burningTransfer(from common.Address, value *big.Int) abi.ReserveTransfer {
 	}
 }
 
+// boundaryAddresses returns a list of addresses that are likely to expose edge cases:
+// the zero address, small and maximal addresses, the address with only the high bit set,
+// the precompiled contracts (0x1 through 0x9), and the addresses of our own deployed contracts.
+func (s *TestSuite) boundaryAddresses() []common.Address {
+	addresses := []common.Address{
+		zeroAddress(),
+		common.BigToAddress(bigInt(1)),
+		common.BigToAddress(bigInt(255)),
+		common.BigToAddress(bigInt(256)),
+		common.BigToAddress(maxUint160()),
+		common.BigToAddress(minInt160AsUint160()),
+	}
+	for i := uint32(2); i <= 9; i++ {
+		addresses = append(addresses, common.BigToAddress(bigInt(i)))
+	}
+	for _, address := range []common.Address{s.reserveAddress, s.eternalStorageAddress} {
+		if address != zeroAddress() {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// eip712DomainSeparator computes the EIP-712 domain separator that Reserve uses, for a
+// contract named `name` on chain `chainID` at `verifyingContract`.
+func eip712DomainSeparator(name string, chainID *big.Int, verifyingContract common.Address) []byte {
+	return crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte(name)),
+		crypto.Keccak256([]byte("1")),
+		abiWord(chainID.Bytes()),
+		abiWord(verifyingContract.Bytes()),
+	)
+}
+
+// signTypedData signs, with `signer`'s key, the EIP-712 message in `domainSeparator` whose
+// ABI-encoded type hash and fields are `words`. It returns the signature in the form that
+// `ecrecover` expects.
+func (s *TestSuite) signTypedData(signer account, domainSeparator []byte, words ...[]byte) (v uint8, r, ss [32]byte) {
+	digest := crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, crypto.Keccak256(words...))
+	sig, err := crypto.Sign(digest, signer.key)
+	s.Require().NoError(err)
+
+	copy(r[:], sig[:32])
+	copy(ss[:], sig[32:64])
+	return sig[64] + 27, r, ss
+}
+
+// abiWord left-pads `b` to a single 32-byte ABI word.
+func abiWord(b []byte) []byte {
+	return common.LeftPadBytes(b, 32)
+}
+
 // shiftLeft returns `n`, shifted left by `decimals` zeroes.
 func shiftLeft(n uint32, decimals uint32) *big.Int {
 	attoBase := big.NewInt(0).Exp(bigInt(10), bigInt(decimals), nil)
 	return big.NewInt(0).Mul(bigInt(n), attoBase)
 }
 
+// toBaseUnits converts `amount` whole tokens to the base units of a token with `decimals`
+// decimals. `amount` is read as the shortest decimal that represents it, so that, for example,
+// 0.1 converts to exactly 10^(decimals-1). Any digits past `decimals` are rounded to the nearest
+// base unit, with halves rounded away from zero.
+func toBaseUnits(amount float64, decimals uint32) *big.Int {
+	exact, ok := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	if !ok {
+		panic(fmt.Sprintf("toBaseUnits: cannot convert %v", amount))
+	}
+	exact.Mul(exact, new(big.Rat).SetInt(shiftLeft(1, decimals)))
+
+	quotient, remainder := new(big.Int).QuoRem(exact.Num(), exact.Denom(), new(big.Int))
+	if remainder.Abs(remainder).Lsh(remainder, 1).Cmp(exact.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(exact.Sign())))
+	}
+	return quotient
+}
+
+// fromBaseUnits converts `amount` base units of a token with `decimals` decimals to whole tokens.
+func fromBaseUnits(amount *big.Int, decimals uint32) *big.Float {
+	const precision = 256
+	return new(big.Float).SetPrec(precision).Quo(
+		new(big.Float).SetPrec(precision).SetInt(amount),
+		new(big.Float).SetPrec(precision).SetInt(shiftLeft(1, decimals)),
+	)
+}
+
 // containsAddress tells whether `a` contains `x`.
 func containsAddress(a []common.Address, x common.Address) bool {
 	for _, n := range a {
@@ -434,6 +1233,166 @@ func containsAddress(a []common.Address, x common.Address) bool {
 
 // TestSuite Helpers
 
+// erc20Spec describes a BasicERC20 for deployERC20s to deploy.
+type erc20Spec struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+}
+
+// erc20Specs returns specs for test tokens with the given decimals, named "Token 0", "Token 1",
+// and so on.
+func erc20Specs(decimals ...uint32) []erc20Spec {
+	specs := make([]erc20Spec, len(decimals))
+	for i, d := range decimals {
+		specs[i] = erc20Spec{
+			Name:     fmt.Sprintf("Token %d", i),
+			Symbol:   fmt.Sprintf("TK%d", i),
+			Decimals: uint8(d),
+		}
+	}
+	return specs
+}
+
+// deployERC20s deploys a BasicERC20 for each spec, each minting its initial supply to
+// s.owner, and makes them s.erc20s and s.erc20Addresses. It also registers them in s.logParsers.
+func (s *TestSuite) deployERC20s(specs []erc20Spec) ([]*abi.BasicERC20, []common.Address) {
+	s.erc20s = make([]*abi.BasicERC20, len(specs))
+	s.erc20Addresses = make([]common.Address, len(specs))
+	for i, spec := range specs {
+		erc20Address, tx, erc20, err := abi.DeployBasicERC20(
+			s.signer, s.node, spec.Name, spec.Symbol, spec.Decimals, s.chainID,
+		)
+		s.logParsers[erc20Address] = erc20
+		s.requireTx(tx, err)
+
+		s.erc20s[i] = erc20
+		s.erc20Addresses[i] = erc20Address
+	}
+	return s.erc20s, s.erc20Addresses
+}
+
+// setupManagerFixture deploys a complete Manager system and wires it together:
+// a Reserve (unpaused, with its ReserveEternalStorage), a Vault, a ProposalFactory,
+// one BasicERC20 per entry in `weights`, a Basket of those tokens, and a Manager.
+// The Manager is taken out of its initial emergency state, and is made the Reserve's minter
+// and pauser and the Vault's manager.
+//
+// `weights` are in whole tokens per RSV, as 18-decimal fixed-point values, and `decimals` gives
+// the number of decimals to assume for each token. The basket weights are therefore
+// `weights[i] * 10^decimals[i]`, in aqToken/RSV.
+//
+// On return, the handles and addresses for every deployed contract are stored in
+// s.reserve, s.eternalStorage, s.vault, s.proposalFactory, s.erc20s, s.basket, and s.manager
+// (and their corresponding address fields), s.weights holds the scaled basket weights, and
+// s.logParsers knows how to parse events from all of them.
+func (s *TestSuite) setupManagerFixture(weights []*big.Int, decimals []uint32) {
+	s.Require().Equal(len(weights), len(decimals), "setupManagerFixture: unequal lengths")
+
+	// Deploy Reserve and store a handle to the Go binding and the contract address.
+	reserveAddress, tx, reserve, err := abi.DeployReserve(s.signer, s.node)
+
+	s.logParsers = map[common.Address]logParser{
+		reserveAddress: reserve,
+	}
+
+	s.requireTx(tx, err)
+	s.reserve = reserve
+	s.reserveAddress = reserveAddress
+
+	// Unpause Reserve.
+	s.requireTxWithStrictEvents(s.reserve.Unpause(s.signer))(
+		abi.ReserveUnpaused{Account: s.owner.address()},
+	)
+
+	// Get the Go binding and contract address for the new ReserveEternalStorage contract.
+	s.eternalStorageAddress, err = s.reserve.GetEternalStorageAddress(nil)
+	s.Require().NoError(err)
+	s.eternalStorage, err = abi.NewReserveEternalStorage(s.eternalStorageAddress, s.node)
+	s.Require().NoError(err)
+
+	s.logParsers[s.eternalStorageAddress] = s.eternalStorage
+
+	// Accept ownership of eternal storage.
+	s.requireTxWithStrictEvents(s.eternalStorage.AcceptOwnership(s.signer))(
+		abi.ReserveEternalStorageOwnershipTransferred{
+			PreviousOwner: s.reserveAddress, NewOwner: s.owner.address(),
+		},
+	)
+
+	// Vault.
+	vaultAddress, tx, vault, err := abi.DeployVault(s.signer, s.node)
+
+	s.logParsers[vaultAddress] = vault
+	s.requireTxWithStrictEvents(tx, err)(
+		abi.VaultOwnershipTransferred{
+			PreviousOwner: zeroAddress(), NewOwner: s.owner.address(),
+		},
+		abi.VaultManagerTransferred{
+			PreviousManager: zeroAddress(), NewManager: s.owner.address(),
+		},
+	)
+	s.vault = vault
+	s.vaultAddress = vaultAddress
+
+	// ProposalFactory.
+	propFactoryAddress, tx, propFactory, err := abi.DeployProposalFactory(s.signer, s.node)
+	s.logParsers[propFactoryAddress] = propFactory
+	s.requireTx(tx, err)
+
+	s.proposalFactory = propFactory
+	s.proposalFactoryAddress = propFactoryAddress
+
+	// Deploy collateral ERC20s, and scale each weight by its token's decimals.
+	s.deployERC20s(erc20Specs(decimals...))
+	s.weights = make([]*big.Int, len(weights))
+	for i := range weights {
+		s.weights[i] = bigInt(0).Mul(weights[i], shiftLeft(1, decimals[i]))
+	}
+
+	// Basket.
+	basketAddress, tx, basket, err := abi.DeployBasket(
+		s.signer, s.node, zeroAddress(), s.erc20Addresses, s.weights,
+	)
+	s.requireTxWithStrictEvents(tx, err)()
+	s.NotEqual(zeroAddress(), basketAddress)
+	s.basketAddress, s.basket = basketAddress, basket
+
+	// Manager.
+	managerAddress, tx, manager, err := abi.DeployManager(
+		s.signer, s.node,
+		vaultAddress, reserveAddress, propFactoryAddress, basketAddress, s.operator.address(), bigInt(0),
+	)
+
+	s.logParsers[managerAddress] = manager
+	s.requireTx(tx, err)(abi.ManagerOwnershipTransferred{
+		PreviousOwner: zeroAddress(), NewOwner: s.owner.address(),
+	})
+	s.manager = manager
+	s.managerAddress = managerAddress
+
+	// Confirm we start in emergency state.
+	emergency, err := s.manager.Emergency(nil)
+	s.Require().NoError(err)
+	s.Equal(true, emergency)
+
+	// Unpause from emergency.
+	s.requireTxWithStrictEvents(s.manager.SetEmergency(signer(s.operator), false))(
+		abi.ManagerEmergencyChanged{OldVal: true, NewVal: false},
+	)
+
+	// Set all auths to Manager.
+	s.requireTxWithStrictEvents(s.reserve.ChangeMinter(s.signer, managerAddress))(
+		abi.ReserveMinterChanged{NewMinter: managerAddress},
+	)
+	s.requireTxWithStrictEvents(s.reserve.ChangePauser(s.signer, managerAddress))(
+		abi.ReservePauserChanged{NewPauser: managerAddress},
+	)
+	s.requireTxWithStrictEvents(s.vault.ChangeManager(s.signer, managerAddress))(
+		abi.VaultManagerTransferred{PreviousManager: s.owner.address(), NewManager: managerAddress},
+	)
+}
+
 func (s *TestSuite) fundAccountWithErc20sAndApprove(acc account, amounts []*big.Int) {
 	// Transfer all of the ERC20 tokens to `proposer`.
 	for i, amount := range amounts {
@@ -509,7 +1468,7 @@ func (s *TestSuite) changeBasketUsingWeightProposal(tokens []common.Address, wei
 	s.requireTxFails(s.manager.ExecuteProposal(signer(s.operator), proposalID))
 
 	// Advance 24h.
-	s.Require().NoError(s.node.(backend).AdjustTime(24 * time.Hour))
+	s.adjustTime(24 * time.Hour)
 
 	// Confirm that non-operators cannot execute the proposal.
 	s.requireTxFails(s.manager.ExecuteProposal(signer(s.account[3]), proposalID))
@@ -553,7 +1512,7 @@ func (s *TestSuite) changeBasketUsingSwapProposal(tokens []common.Address, amoun
 	s.requireTxFails(s.manager.ExecuteProposal(signer(s.operator), proposalID))
 
 	// Advance 24h.
-	s.Require().NoError(s.node.(backend).AdjustTime(24 * time.Hour))
+	s.adjustTime(24 * time.Hour)
 
 	// Confirm that non-operators cannot execute the proposal.
 	s.requireTxFails(s.manager.ExecuteProposal(signer(s.account[3]), proposalID))
@@ -5,8 +5,10 @@ package tests
 import (
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/suite"
 
@@ -14,7 +16,7 @@ import (
 )
 
 func TestBasket(t *testing.T) {
-	suite.Run(t, new(BasketSuite))
+	runSuite(t, new(BasketSuite))
 }
 
 type BasketSuite struct {
@@ -38,15 +40,10 @@ func (s *BasketSuite) SetupSuite() {
 
 // BeforeTest runs before each test in the suite.
 func (s *BasketSuite) BeforeTest(suiteName, testName string) {
+	s.logParsers = map[common.Address]logParser{}
+
 	// Deploy collateral ERC20s
-	s.erc20s = make([]*abi.BasicERC20, 3)
-	s.erc20Addresses = make([]common.Address, 3)
-	for i := 0; i < 3; i++ {
-		erc20Address, _, erc20, err := abi.DeployBasicERC20(s.signer, s.node)
-		s.Require().NoError(err)
-		s.erc20s[i] = erc20
-		s.erc20Addresses[i] = erc20Address
-	}
+	s.deployERC20s(erc20Specs(18, 18, 18))
 
 	s.weights = []*big.Int{shiftLeft(1, 36), shiftLeft(2, 36), shiftLeft(3, 36)}
 
@@ -100,6 +97,61 @@ func (s *BasketSuite) TestViews() {
 	s.Equal(false, foundHas)
 }
 
+// TestIndexOf checks that `indexOf` finds each token's position, and reverts for absent tokens.
+func (s *BasketSuite) TestIndexOf() {
+	for i, token := range s.erc20Addresses {
+		index, err := s.basket.IndexOf(nil, token)
+		s.Require().NoError(err)
+		s.Equal(uint64(i), index.Uint64())
+
+		s.assertBasketContains(token, s.weights[i])
+	}
+
+	_, err := s.basket.IndexOf(nil, s.account[3].address())
+	s.Error(err)
+}
+
+// TestWeightsSum checks that `weightsSum` adds up the weights of every token in the basket,
+// including those carried over from a previous basket.
+func (s *BasketSuite) TestWeightsSum() {
+	s.assertBasket(s.basket, s.erc20Addresses, s.weights)
+	s.assertBasketWeightsSum(s.basket, sumWeights(s.weights))
+
+	// Override the first token's weight and add a new token.
+	newToken := s.account[3].address()
+	tokens := []common.Address{s.erc20Addresses[0], newToken}
+	weights := []*big.Int{shiftLeft(7, 17), shiftLeft(5, 17)}
+	_, tx, basket, err := abi.DeployBasket(s.signer, s.node, s.basketAddress, tokens, weights)
+	s.requireTxWithStrictEvents(tx, err)()
+
+	expectedTokens := append(append([]common.Address{}, tokens...), s.erc20Addresses[1:]...)
+	expectedWeights := append(append([]*big.Int{}, weights...), s.weights[1:]...)
+	s.assertBasket(basket, expectedTokens, expectedWeights)
+	s.assertBasketWeightsSum(basket, sumWeights(expectedWeights))
+
+	// An empty basket sums to zero.
+	_, tx, emptyBasket, err := abi.DeployBasket(s.signer, s.node, zeroAddress(), nil, nil)
+	s.requireTxWithStrictEvents(tx, err)()
+	s.assertBasketWeightsSum(emptyBasket, bigInt(0))
+}
+
+// TestBasketIsImmutable checks that a Basket can't change once it's deployed: it has no
+// mutating functions, and deriving a new basket from it leaves it as it was.
+func (s *BasketSuite) TestBasketIsImmutable() {
+	basketABI, err := ethabi.JSON(strings.NewReader(abi.BasketABI))
+	s.Require().NoError(err)
+	for name, method := range basketABI.Methods {
+		s.Truef(method.Const, "Basket.%v is not a view", name)
+	}
+
+	tokens := []common.Address{s.erc20Addresses[0], s.account[3].address()}
+	weights := []*big.Int{shiftLeft(7, 17), shiftLeft(5, 17)}
+	_, tx, _, err := abi.DeployBasket(s.signer, s.node, s.basketAddress, tokens, weights)
+	s.requireTxWithStrictEvents(tx, err)()
+
+	s.assertBasket(s.basket, s.erc20Addresses, s.weights)
+}
+
 // TestSuccessiveBasketWithEmptyParams tries deploying a second basket from a different account.
 // This basket has no tokens, so should carry over tokens from the first basket.
 func (s *BasketSuite) TestSuccessiveBasketWithEmptyParams() {
@@ -238,6 +290,64 @@ func (s *BasketSuite) TestSuccessiveBasketWithAdditionalTokens() {
 	s.Equal(true, newHas)
 }
 
+// TestDeployERC20s tests that deployERC20s deploys tokens with the requested details, and mints
+// their supply to the deployer.
+func (s *BasketSuite) TestDeployERC20s() {
+	specs := []erc20Spec{
+		{Name: "USD Coin", Symbol: "USDC", Decimals: 6},
+		{Name: "Wrapped Bitcoin", Symbol: "WBTC", Decimals: 8},
+		{Name: "Dai", Symbol: "DAI", Decimals: 18},
+	}
+	erc20s, addresses := s.deployERC20s(specs)
+	s.Require().Len(erc20s, 3)
+	s.Equal(addresses, s.erc20Addresses)
+
+	for i, erc20 := range erc20s {
+		decimals, err := erc20.Decimals(nil)
+		s.Require().NoError(err)
+		s.Equal(specs[i].Decimals, decimals)
+
+		name, err := erc20.Name(nil)
+		s.Require().NoError(err)
+		s.Equal(specs[i].Name, name)
+
+		symbol, err := erc20.Symbol(nil)
+		s.Require().NoError(err)
+		s.Equal(specs[i].Symbol, symbol)
+
+		initialSupply, ok := new(big.Int).SetString("1"+strings.Repeat("0", 48), 10)
+		s.Require().True(ok)
+		s.assertERC20Balance(erc20, s.owner.address(), initialSupply)
+		s.assertERC20TotalSupply(erc20, initialSupply)
+	}
+}
+
+// TestERC20Transfer tests that the basket's BasicERC20s transfer balances without changing their
+// total supply.
+func (s *BasketSuite) TestERC20Transfer() {
+	recipient := s.account[1].address()
+	amount := bigInt(250)
+
+	for _, erc20 := range s.erc20s {
+		totalSupply, err := erc20.TotalSupply(nil)
+		s.Require().NoError(err)
+		ownerBalance, err := erc20.BalanceOf(nil, s.owner.address())
+		s.Require().NoError(err)
+
+		s.requireTxWithStrictEvents(erc20.Transfer(s.signer, recipient, amount))(
+			abi.BasicERC20Transfer{From: s.owner.address(), To: recipient, Value: amount},
+		)
+
+		s.assertERC20Balance(erc20, recipient, amount)
+		s.assertERC20Balance(erc20, s.owner.address(), new(big.Int).Sub(ownerBalance, amount))
+		s.assertERC20TotalSupply(erc20, totalSupply)
+	}
+
+	// Transferring more than the sender holds fails, and changes nothing.
+	s.requireTxFails(s.erc20s[0].Transfer(signer(s.account[1]), s.owner.address(), bigInt(251)))
+	s.assertERC20Balance(s.erc20s[0], recipient, amount)
+}
+
 // TestNegativeCases checks to make sure invalid basket constructions revert.
 func (s *BasketSuite) TestNegativeCases() {
 	// Case 1: Tokens is longer than Weights.
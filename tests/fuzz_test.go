@@ -3,7 +3,6 @@
 package tests
 
 import (
-	"context"
 	"flag"
 	"fmt"
 	"math/big"
@@ -14,7 +13,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/suite"
@@ -23,7 +21,7 @@ import (
 )
 
 func TestManagerFuzz(t *testing.T) {
-	suite.Run(t, new(ManagerFuzzSuite))
+	runSuite(t, new(ManagerFuzzSuite))
 }
 
 type ManagerFuzzSuite struct {
@@ -35,6 +33,7 @@ type ManagerFuzzSuite struct {
 
 var duration = flag.Int("runs", 10, "transactions to randomly generate")
 var decimals = flag.String("decimals", "6,18,6", "number of decimals for each token")
+var fuzzSeed = flag.Int64("seed", 0, "random seed for TestFuzzTransferInvariants; 0 picks one from the clock")
 
 // Limitations: Only up to 10 tokens max
 
@@ -118,16 +117,9 @@ func (s *ManagerFuzzSuite) BeforeTest(suiteName, testName string) {
 	s.requireTx(tx, err)
 
 	// Deploy collateral ERC20s.
-	s.erc20s = make([]*abi.BasicERC20, s.numTokens)
-	s.erc20Addresses = make([]common.Address, s.numTokens)
-	for i := 0; i < s.numTokens; i++ {
-		erc20Address, _, erc20, err := abi.DeployBasicERC20(s.signer, s.node)
-		s.Require().NoError(err)
-
+	s.deployERC20s(erc20Specs(s.decimals[:s.numTokens]...))
+	for i, erc20Address := range s.erc20Addresses {
 		s.addressToDecimals[erc20Address] = s.decimals[i]
-		s.erc20s[i] = erc20
-		s.erc20Addresses[i] = erc20Address
-		s.logParsers[erc20Address] = erc20
 	}
 
 	// Make a simple basket
@@ -441,7 +433,7 @@ func (s *ManagerFuzzSuite) tryWeightProposal(tokens []common.Address, weights []
 	s.requireTxFails(s.manager.ExecuteProposal(signer(s.operator), proposalID))
 
 	// Advance 24h.
-	s.Require().NoError(s.node.(backend).AdjustTime(24 * time.Hour))
+	s.adjustTime(24 * time.Hour)
 
 	// Try to execute the Proposal, but it's okay if it fails.
 	s.displayTxResult(s.manager.ExecuteProposal(signer(s.operator), proposalID))
@@ -480,7 +472,7 @@ func (s *ManagerFuzzSuite) trySwapProposal(tokens []common.Address, amounts []*b
 	s.requireTxFails(s.manager.ExecuteProposal(signer(s.operator), proposalID))
 
 	// Advance 24h.
-	s.Require().NoError(s.node.(backend).AdjustTime(24 * time.Hour))
+	s.adjustTime(24 * time.Hour)
 
 	// Try to execute the Proposal.
 	s.displayTxResult(s.manager.ExecuteProposal(signer(s.operator), proposalID))
@@ -606,7 +598,7 @@ func (s *ManagerFuzzSuite) printRoundingError(oldBalances []*big.Int) {
 // displayTxResult prints whether or not the tx succeeded.
 func (s *ManagerFuzzSuite) displayTxResult(tx *types.Transaction, err error) {
 	if err == nil {
-		receipt, err := bind.WaitMined(context.Background(), s.node, tx)
+		receipt, err := waitMined(s.node, tx, s.mineTimeout)
 		s.Require().NoError(err)
 		if receipt.Status == types.ReceiptStatusSuccessful {
 			fmt.Printf(" | ✅")
@@ -0,0 +1,32 @@
+// +build all fuzz
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain starts the shared coverage node before the suites run, when coverage or gas profiling
+// is enabled, and stops it once they're done.
+func TestMain(m *testing.M) {
+	if coverageEnabled || gasProfileEnabled {
+		if err := startCoverageNode(); err != nil {
+			fmt.Fprintln(os.Stderr, "starting the coverage node:", err)
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+
+	if coverageNode != nil {
+		if err := stopCoverageNode(); err != nil {
+			fmt.Fprintln(os.Stderr, "stopping the coverage node:", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+	os.Exit(code)
+}
@@ -5,15 +5,18 @@ package tests
 import (
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/reserve-protocol/rsv-beta/abi"
+	"github.com/reserve-protocol/rsv-beta/ops"
 )
 
 func TestManager(t *testing.T) {
-	suite.Run(t, new(ManagerSuite))
+	runSuite(t, new(ManagerSuite))
 }
 
 type ManagerSuite struct {
@@ -40,120 +43,10 @@ func (s *ManagerSuite) BeforeTest(suiteName, testName string) {
 	s.operator = s.account[1]
 	s.proposer = s.account[5]
 
-	// Deploy Reserve and store a handle to the Go binding and the contract address.
-	reserveAddress, tx, reserve, err := abi.DeployReserve(s.signer, s.node)
-
-	s.logParsers = map[common.Address]logParser{
-		reserveAddress: reserve,
-	}
-
-	s.requireTx(tx, err)
-	s.reserve = reserve
-	s.reserveAddress = reserveAddress
-
-	// Unpause Reserve.
-	s.requireTxWithStrictEvents(s.reserve.Unpause(s.signer))(
-		abi.ReserveUnpaused{Account: s.owner.address()},
-	)
-
-	// Get the Go binding and contract address for the new ReserveEternalStorage contract.
-	s.eternalStorageAddress, err = s.reserve.GetEternalStorageAddress(nil)
-	s.Require().NoError(err)
-	s.eternalStorage, err = abi.NewReserveEternalStorage(s.eternalStorageAddress, s.node)
-	s.Require().NoError(err)
-
-	s.logParsers[s.eternalStorageAddress] = s.eternalStorage
-
-	// Accept ownership of eternal storage.
-	s.requireTxWithStrictEvents(s.eternalStorage.AcceptOwnership(s.signer))(
-		abi.ReserveEternalStorageOwnershipTransferred{
-			PreviousOwner: s.reserveAddress, NewOwner: s.account[0].address(),
-		},
-	)
-
-	// Vault.
-	vaultAddress, tx, vault, err := abi.DeployVault(s.signer, s.node)
-
-	s.logParsers[vaultAddress] = vault
-	s.requireTxWithStrictEvents(tx, err)(
-		abi.VaultOwnershipTransferred{
-			PreviousOwner: zeroAddress(), NewOwner: s.owner.address(),
-		},
-		abi.VaultManagerTransferred{
-			PreviousManager: zeroAddress(), NewManager: s.owner.address(),
-		},
-	)
-	s.vault = vault
-	s.vaultAddress = vaultAddress
-
-	// ProposalFactory.
-	propFactoryAddress, tx, propFactory, err := abi.DeployProposalFactory(s.signer, s.node)
-	s.logParsers[propFactoryAddress] = propFactory
-	s.requireTx(tx, err)
-
-	s.proposalFactory = propFactory
-	s.proposalFactoryAddress = propFactoryAddress
-
-	// Deploy collateral ERC20s.
-	s.erc20s = make([]*abi.BasicERC20, 3)
-	s.erc20Addresses = make([]common.Address, 3)
-	for i := 0; i < 3; i++ {
-		erc20Address, _, erc20, err := abi.DeployBasicERC20(s.signer, s.node)
-		s.Require().NoError(err)
-
-		s.erc20s[i] = erc20
-		s.erc20Addresses[i] = erc20Address
-		s.logParsers[erc20Address] = erc20
-	}
-
-	// Basket.
-	s.weights = []*big.Int{shiftLeft(1, 36), shiftLeft(2, 36), shiftLeft(3, 36)}
-
-	// Make a simple basket
-	basketAddress, tx, basket, err := abi.DeployBasket(
-		s.signer, s.node, zeroAddress(), s.erc20Addresses, s.weights,
-	)
-	s.requireTxWithStrictEvents(tx, err)
-	s.NotEqual(zeroAddress(), basketAddress)
-	s.basketAddress, s.basket = basketAddress, basket
-
-	// Manager.
-	managerAddress, tx, manager, err := abi.DeployManager(
-		s.signer, s.node,
-		vaultAddress, reserveAddress, propFactoryAddress, basketAddress, s.operator.address(), bigInt(0),
-	)
-
-	s.logParsers[managerAddress] = manager
-	s.requireTx(tx, err)(abi.ManagerOwnershipTransferred{
-		PreviousOwner: zeroAddress(), NewOwner: s.owner.address(),
-	})
-	s.manager = manager
-	s.managerAddress = managerAddress
-
-	// Confirm we start in emergency state.
-	emergency, err := s.manager.Emergency(nil)
-	s.Require().NoError(err)
-	s.Equal(true, emergency)
-
-	// Unpause from emergency.
-	s.requireTxWithStrictEvents(s.manager.SetEmergency(signer(s.operator), false))(
-		abi.ManagerEmergencyChanged{OldVal: true, NewVal: false},
-	)
-
-	// Confirm we are unpaused from emergency.
-	emergency, err = s.manager.Emergency(nil)
-	s.Require().NoError(err)
-	s.Equal(false, emergency)
-
-	// Set all auths to Manager.
-	s.requireTxWithStrictEvents(s.reserve.ChangeMinter(s.signer, managerAddress))(
-		abi.ReserveMinterChanged{NewMinter: managerAddress},
-	)
-	s.requireTxWithStrictEvents(s.reserve.ChangePauser(s.signer, managerAddress))(
-		abi.ReservePauserChanged{NewPauser: managerAddress},
-	)
-	s.requireTxWithStrictEvents(s.vault.ChangeManager(s.signer, managerAddress))(
-		abi.VaultManagerTransferred{PreviousManager: s.owner.address(), NewManager: managerAddress},
+	// Deploy a basket of three 18-decimal tokens, with weights 1, 2, and 3 tokens per RSV.
+	s.setupManagerFixture(
+		[]*big.Int{shiftLeft(1, 18), shiftLeft(2, 18), shiftLeft(3, 18)},
+		[]uint32{18, 18, 18},
 	)
 
 	// Fund and set allowances.
@@ -312,6 +205,29 @@ func (s *ManagerSuite) TestSetSeigniorageRequires() {
 	s.requireTxFails(s.manager.SetSeigniorage(s.signer, seigniorage))
 }
 
+// TestSetRedeemFee tests that `setRedeemFee` manipulates state correctly, and caps the fee.
+func (s *ManagerSuite) TestSetRedeemFee() {
+	redeemFee := bigInt(25)
+	s.requireTxWithStrictEvents(s.manager.SetRedeemFee(s.signer, redeemFee))(
+		abi.ManagerRedeemFeeChanged{
+			OldVal: bigInt(0), NewVal: redeemFee,
+		},
+	)
+
+	// Check that state is correct.
+	foundRedeemFee, err := s.manager.RedeemFee(nil)
+	s.Require().NoError(err)
+	s.Equal(redeemFee.String(), foundRedeemFee.String())
+
+	s.requireTxFails(s.manager.SetRedeemFee(s.signer, bigInt(1001)))
+}
+
+// TestSetRedeemFeeIsProtected tests that `setRedeemFee` can only be called by owner.
+func (s *ManagerSuite) TestSetRedeemFeeIsProtected() {
+	s.requireTxFails(s.manager.SetRedeemFee(signer(s.account[2]), bigInt(1)))
+	s.requireTxFails(s.manager.SetRedeemFee(signer(s.operator), bigInt(1)))
+}
+
 // TestSetDelay tests that `setDelay` manipulates state correctly.
 func (s *ManagerSuite) TestSetDelay() {
 	delay := bigInt(172800) // 48 hours
@@ -334,6 +250,88 @@ func (s *ManagerSuite) TestSetDelayIsProtected() {
 	s.requireTxFails(s.manager.SetDelay(signer(s.operator), delay))
 }
 
+// TestSetMaxBasketSize tests that `setMaxBasketSize` manipulates state correctly, and that
+// proposals over the cap are rejected.
+func (s *ManagerSuite) TestSetMaxBasketSize() {
+	maxBasketSize, err := s.manager.MaxBasketSize(nil)
+	s.Require().NoError(err)
+	s.Equal("10", maxBasketSize.String())
+
+	s.requireTxWithStrictEvents(s.manager.SetMaxBasketSize(s.signer, bigInt(3)))(
+		abi.ManagerMaxBasketSizeChanged{
+			OldVal: bigInt(10), NewVal: bigInt(3),
+		},
+	)
+
+	maxBasketSize, err = s.manager.MaxBasketSize(nil)
+	s.Require().NoError(err)
+	s.Equal("3", maxBasketSize.String())
+
+	// A proposal at the cap succeeds.
+	s.requireTx(s.manager.ProposeWeights(signer(s.proposer), s.erc20Addresses, s.weights))
+
+	// One over the cap reverts.
+	tokens := append(append([]common.Address{}, s.erc20Addresses...), s.account[5].address())
+	weights := append(append([]*big.Int{}, s.weights...), shiftLeft(1, 35))
+	s.requireTxRevertsWith(s.manager.ProposeWeights(withGasLimit(signer(s.proposer), 5e6), tokens, weights))(
+		"proposeWeights: too many tokens",
+	)
+	s.requireTxRevertsWith(s.manager.ProposeSwap(
+		withGasLimit(signer(s.proposer), 5e6), tokens, weights, []bool{true, true, true, true},
+	))("proposeSwap: too many tokens")
+
+	// A swap is limited by the size of the basket it would produce, not by how many tokens it
+	// names: swapping one new token into the 3-token basket needs room for 4.
+	newToken := []common.Address{s.account[5].address()}
+	amounts := []*big.Int{bigInt(1)}
+	s.requireTxRevertsWith(s.manager.ProposeSwap(
+		withGasLimit(signer(s.proposer), 5e6), newToken, amounts, []bool{true},
+	))("proposeSwap: too many tokens")
+	s.requireTx(s.manager.ProposeSwap(
+		signer(s.proposer), s.erc20Addresses[:1], amounts, []bool{true},
+	))
+	s.requireTx(s.manager.SetMaxBasketSize(s.signer, bigInt(4)))
+	s.requireTx(s.manager.ProposeSwap(signer(s.proposer), newToken, amounts, []bool{true}))
+
+	// A new token listed twice still only takes one place in the basket.
+	s.requireTx(s.manager.ProposeSwap(
+		signer(s.proposer),
+		[]common.Address{s.account[5].address(), s.account[5].address()},
+		[]*big.Int{bigInt(1), bigInt(1)},
+		[]bool{true, true},
+	))
+
+	// The cap must leave room for at least one token.
+	s.requireTxRevertsWith(s.manager.SetMaxBasketSize(withGasLimit(s.signer, 1e6), bigInt(0)))(
+		"max basket size must be at least 1",
+	)
+}
+
+// TestExecuteProposalOverMaxBasketSize tests that a proposal accepted under the basket size cap
+// can't be executed once the cap drops below the size of the basket it would produce.
+func (s *ManagerSuite) TestExecuteProposalOverMaxBasketSize() {
+	newWeights := []*big.Int{shiftLeft(2, 35), shiftLeft(3, 35), shiftLeft(5, 35)}
+	s.requireTx(s.manager.ProposeWeights(signer(s.proposer), s.erc20Addresses, newWeights))
+	s.requireTx(s.manager.AcceptProposal(signer(s.operator), bigInt(1)))
+	s.adjustTime(24 * time.Hour)
+
+	s.requireTx(s.manager.SetMaxBasketSize(s.signer, bigInt(2)))
+	s.requireTxRevertsWith(s.manager.ExecuteProposal(withGasLimit(signer(s.operator), 5e6), bigInt(1)))(
+		"basket has too many tokens",
+	)
+
+	// Once the cap is raised again, the proposal executes.
+	s.requireTx(s.manager.SetMaxBasketSize(s.signer, bigInt(3)))
+	s.requireTx(s.manager.ExecuteProposal(signer(s.operator), bigInt(1)))
+	s.assertManagerCollateralized()
+}
+
+// TestSetMaxBasketSizeIsProtected tests that `setMaxBasketSize` can only be called by owner.
+func (s *ManagerSuite) TestSetMaxBasketSizeIsProtected() {
+	s.requireTxFails(s.manager.SetMaxBasketSize(signer(s.account[2]), bigInt(1)))
+	s.requireTxFails(s.manager.SetMaxBasketSize(signer(s.operator), bigInt(1)))
+}
+
 // TestClearProposals tests that `clearProposals` manipulates state correctly.
 func (s *ManagerSuite) TestClearProposals() {
 	// ProposalsLength should start at 1.
@@ -375,7 +373,9 @@ func (s *ManagerSuite) TestIssue() {
 	s.fundAccountWithErc20sAndApprove(buyer, expectedAmounts)
 
 	// Issue.
-	s.requireTx(s.manager.Issue(signer(buyer), rsvAmount))
+	tx, err := s.manager.Issue(signer(buyer), rsvAmount)
+	s.requireTx(tx, err)
+	s.requireNoDuplicateEvents(s.receipt(tx))
 
 	// Expect RSV balance.
 	balance, err := s.reserve.BalanceOf(nil, buyer.address())
@@ -397,6 +397,278 @@ func (s *ManagerSuite) TestIssue() {
 	s.assertManagerCollateralized()
 }
 
+// TestIssueTo tests that issueTo takes collateral from the sender and mints RSV to the recipient.
+func (s *ManagerSuite) TestIssueTo() {
+	payer := s.account[1]
+	recipient := s.account[2]
+
+	rsvAmount := shiftLeft(1, 27) // 1 billion
+	expectedAmounts := s.computeExpectedIssueAmounts(bigInt(0), rsvAmount)
+	s.fundAccountWithErc20sAndApprove(payer, expectedAmounts)
+
+	vaultBefore := make([]*big.Int, len(s.erc20s))
+	for i, erc20 := range s.erc20s {
+		balance, err := erc20.BalanceOf(nil, s.vaultAddress)
+		s.Require().NoError(err)
+		vaultBefore[i] = balance
+	}
+
+	s.requireTx(s.manager.IssueTo(signer(payer), recipient.address(), rsvAmount))(
+		abi.ManagerIssuance{User: recipient.address(), Amount: rsvAmount},
+		abi.ManagerIssuanceTo{Payer: payer.address(), Recipient: recipient.address(), Amount: rsvAmount},
+	)
+
+	// The recipient gets the RSV, and the payer pays the collateral.
+	s.assertRSVBalance(recipient.address(), rsvAmount)
+	s.assertRSVBalance(payer.address(), bigInt(0))
+	for i, erc20 := range s.erc20s {
+		s.assertERC20Balance(erc20, payer.address(), bigInt(0))
+		s.assertERC20Balance(erc20, s.vaultAddress, bigInt(0).Add(vaultBefore[i], expectedAmounts[i]))
+	}
+	s.assertManagerCollateralized()
+
+	// RSV can't be issued to an account that can't receive it.
+	s.fundAccountWithErc20sAndApprove(payer, expectedAmounts)
+	s.requireTxFails(s.manager.IssueTo(signer(payer), zeroAddress(), rsvAmount))
+	s.requireTxFails(s.manager.IssueTo(signer(payer), s.reserveAddress, rsvAmount))
+}
+
+// TestIssueWithPermit tests that a buyer can let the Manager take its collateral with EIP-2612
+// permits, instead of approve transactions, and then issue.
+func (s *ManagerSuite) TestIssueWithPermit() {
+	buyer := s.account[4]
+	relayer := s.account[3]
+
+	rsvAmount := shiftLeft(1, 27) // 1 billion
+	amounts := s.computeExpectedIssueAmounts(bigInt(0), rsvAmount)
+	for i, erc20 := range s.erc20s {
+		s.requireTx(erc20.Transfer(s.signer, buyer.address(), amounts[i]))
+	}
+
+	// Someone else submits the buyer's permits, so the buyer only sends the issuance itself.
+	deadline := bigInt(0).Add(s.currentTimestamp(), bigInt(3600))
+	for i, erc20 := range s.erc20s {
+		v, r, ss := s.signPermit(erc20, s.erc20Addresses[i], buyer, s.managerAddress, amounts[i], deadline)
+
+		// The same signature with a high s value, and v flipped between 27 and 28 to match, is
+		// rejected.
+		highS := bigInt(0).Sub(crypto.S256().Params().N, bigInt(0).SetBytes(ss[:]))
+		var malleated [32]byte
+		copy(malleated[:], abiWord(highS.Bytes()))
+		s.requireTxRevertsWith(erc20.Permit(
+			withGasLimit(signer(relayer), 1e6),
+			buyer.address(), s.managerAddress, amounts[i], deadline, 55-v, r, malleated,
+		))("invalid signature 's' value")
+
+		s.requireTxWithStrictEvents(erc20.Permit(
+			signer(relayer), buyer.address(), s.managerAddress, amounts[i], deadline, v, r, ss,
+		))(
+			abi.BasicERC20Approval{Owner: buyer.address(), Spender: s.managerAddress, Value: amounts[i]},
+		)
+		s.assertERC20Allowance(erc20, buyer.address(), s.managerAddress, amounts[i])
+
+		// A permit can't be replayed.
+		s.requireTxFails(erc20.Permit(
+			signer(relayer), buyer.address(), s.managerAddress, amounts[i], deadline, v, r, ss,
+		))
+	}
+
+	s.requireTx(s.manager.Issue(signer(buyer), rsvAmount))(
+		abi.ManagerIssuance{User: buyer.address(), Amount: rsvAmount},
+	)
+	s.assertRSVBalance(buyer.address(), rsvAmount)
+	for _, erc20 := range s.erc20s {
+		s.assertERC20Balance(erc20, buyer.address(), bigInt(0))
+	}
+	s.assertManagerCollateralized()
+}
+
+// signPermit signs an EIP-2612 permit, from `owner`, for `spender` to spend `value` of the token
+// `erc20` at `erc20Address` until `deadline`.
+func (s *ManagerSuite) signPermit(
+	erc20 *abi.BasicERC20, erc20Address common.Address,
+	owner account, spender common.Address, value, deadline *big.Int,
+) (uint8, [32]byte, [32]byte) {
+	name, err := erc20.Name(nil)
+	s.Require().NoError(err)
+	domainSeparator, err := erc20.DOMAINSEPARATOR(nil)
+	s.Require().NoError(err)
+	s.Require().Equal(eip712DomainSeparator(name, s.chainID, erc20Address), domainSeparator[:])
+
+	nonce, err := erc20.Nonces(nil, owner.address())
+	s.Require().NoError(err)
+
+	return s.signTypedData(
+		owner,
+		domainSeparator[:],
+		crypto.Keccak256([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)")),
+		abiWord(owner.address().Bytes()),
+		abiWord(spender.Bytes()),
+		abiWord(value.Bytes()),
+		abiWord(nonce.Bytes()),
+		abiWord(deadline.Bytes()),
+	)
+}
+
+// TestBackingForAmount tests that backingForAmount previews the collateral an issuance takes.
+func (s *ManagerSuite) TestBackingForAmount() {
+	rsvAmount := shiftLeft(100, 18) // 100 RSV
+	backing, err := s.manager.BackingForAmount(nil, rsvAmount)
+	s.Require().NoError(err)
+	s.Equal(s.erc20Addresses, backing.Tokens)
+	s.Require().Len(backing.Amounts, len(s.erc20s))
+
+	before := make([]*big.Int, len(s.erc20s))
+	for i, erc20 := range s.erc20s {
+		balance, err := erc20.BalanceOf(nil, s.vaultAddress)
+		s.Require().NoError(err)
+		before[i] = balance
+	}
+
+	s.requireTx(s.manager.Issue(signer(s.proposer), rsvAmount))
+	for i, erc20 := range s.erc20s {
+		s.assertERC20Balance(erc20, s.vaultAddress, bigInt(0).Add(before[i], backing.Amounts[i]))
+	}
+}
+
+// TestSimulateIssue tests that ops.SimulateIssue predicts the outcome of a real issuance, and
+// reports the revert reason of an issuance that would fail.
+func (s *ManagerSuite) TestSimulateIssue() {
+	buyer := s.account[4]
+	rsvAmount := shiftLeft(1, 21)
+	expectedAmounts := s.computeExpectedIssueAmounts(bigInt(0), rsvAmount)
+	s.fundAccountWithErc20sAndApprove(buyer, expectedAmounts)
+
+	// Issuing zero RSV would revert.
+	sim, err := ops.SimulateIssue(s.node, s.managerAddress, buyer.address(), bigInt(0))
+	s.Require().NoError(err)
+	s.True(sim.Reverted)
+	s.Equal("cannot issue zero RSV", sim.RevertReason)
+
+	sim, err = ops.SimulateIssue(s.node, s.managerAddress, buyer.address(), rsvAmount)
+	s.Require().NoError(err)
+	s.False(sim.Reverted)
+	s.Equal(s.erc20Addresses, sim.Tokens)
+
+	// Simulating doesn't change any state.
+	s.assertRSVBalance(buyer.address(), bigInt(0))
+
+	// Issue for real, and compare.
+	vaultBefore := make([]*big.Int, len(s.erc20s))
+	for i, erc20 := range s.erc20s {
+		vaultBefore[i], err = erc20.BalanceOf(nil, s.vaultAddress)
+		s.Require().NoError(err)
+	}
+	s.requireTx(s.manager.Issue(signer(buyer), rsvAmount))
+
+	s.assertRSVBalance(buyer.address(), sim.RSVMinted)
+	for i, erc20 := range s.erc20s {
+		vaultAfter, err := erc20.BalanceOf(nil, s.vaultAddress)
+		s.Require().NoError(err)
+		s.Equal(sim.Collateral[i].String(), bigInt(0).Sub(vaultAfter, vaultBefore[i]).String())
+	}
+}
+
+// TestIssueWithMixedDecimals tests issuance against a basket whose tokens use different decimals.
+func (s *ManagerSuite) TestIssueWithMixedDecimals() {
+	buyer := s.account[4]
+	decimals := []uint32{6, 18, 8}
+	tokensPerRSV := []float64{0.5, 0.3, 0.2}
+
+	// Redeploy the system with a basket of 0.5, 0.3, and 0.2 tokens per RSV.
+	weights := make([]*big.Int, len(tokensPerRSV))
+	for i, w := range tokensPerRSV {
+		weights[i] = toBaseUnits(w, 18)
+	}
+	s.setupManagerFixture(weights, decimals)
+	s.assertBasket(s.basket, s.erc20Addresses, s.weights)
+
+	// Issuing 2.5 RSV should take exactly 2.5 times the basket, in each token's own decimals.
+	rsvAmount := toBaseUnits(2.5, 18)
+	expectedAmounts := s.computeExpectedIssueAmounts(bigInt(0), rsvAmount)
+	for i, d := range decimals {
+		s.Equal(toBaseUnits(2.5*tokensPerRSV[i], d).String(), expectedAmounts[i].String())
+	}
+	s.fundAccountWithErc20sAndApprove(buyer, expectedAmounts)
+
+	s.requireTx(s.manager.Issue(signer(buyer), rsvAmount))
+	s.assertRSVBalance(buyer.address(), rsvAmount)
+	for i, erc20 := range s.erc20s {
+		balance, err := erc20.BalanceOf(nil, s.vaultAddress)
+		s.Require().NoError(err)
+		s.Equal(expectedAmounts[i].String(), balance.String())
+		s.Equal(
+			fromBaseUnits(toBaseUnits(2.5*tokensPerRSV[i], decimals[i]), decimals[i]).String(),
+			fromBaseUnits(balance, decimals[i]).String(),
+		)
+	}
+	s.assertManagerCollateralized()
+}
+
+// TestQuoteIssueWithRemainder tests that quoteIssueWithRemainder reports the same amounts as
+// toIssue, with nonzero remainders exactly when the amounts had to be rounded up.
+func (s *ManagerSuite) TestQuoteIssueWithRemainder() {
+	scaleFactor := shiftLeft(1, 36)
+
+	// 1 qRSV can't be backed by a whole number of qTokens, so every amount is rounded up.
+	rsvAmount := bigInt(1)
+	quote, err := s.manager.QuoteIssueWithRemainder(nil, rsvAmount)
+	s.Require().NoError(err)
+	s.Equal(s.erc20Addresses, quote.Tokens)
+
+	toIssue, err := s.manager.ToIssue(nil, rsvAmount)
+	s.Require().NoError(err)
+	s.Equal(toIssue, quote.Amounts)
+
+	s.Require().Equal(len(s.weights), len(quote.Remainders))
+	for i, weight := range s.weights {
+		expected := bigInt(0).Mod(bigInt(0).Mul(rsvAmount, weight), scaleFactor)
+		s.NotEqual("0", quote.Remainders[i].String())
+		s.Equal(expected.String(), quote.Remainders[i].String())
+	}
+
+	// A whole RSV divides evenly by the basket weights, so there are no remainders.
+	quote, err = s.manager.QuoteIssueWithRemainder(nil, shiftLeft(1, 18))
+	s.Require().NoError(err)
+	for _, remainder := range quote.Remainders {
+		s.Equal("0", remainder.String())
+	}
+}
+
+// TestDriftAfterIssue tests that issuance in basket proportions doesn't increase drift, and that
+// an imbalanced Vault reports positive drift.
+func (s *ManagerSuite) TestDriftAfterIssue() {
+	rsvAmount := shiftLeft(1, 21)
+
+	// The Vault starts empty with no RSV supply, so there is no drift before or after issuance.
+	drift, err := s.manager.DriftAfterIssue(nil, bigInt(0))
+	s.Require().NoError(err)
+	s.Equal("0", drift.String())
+	drift, err = s.manager.DriftAfterIssue(nil, rsvAmount)
+	s.Require().NoError(err)
+	s.Equal("0", drift.String())
+
+	s.requireTx(s.manager.Issue(signer(s.proposer), rsvAmount))
+	drift, err = s.manager.DriftAfterIssue(nil, bigInt(0))
+	s.Require().NoError(err)
+	s.Equal("0", drift.String())
+
+	// Imbalance the Vault by sending it extra of the first token.
+	// With a weight of 0.1 token per RSV, 1 extra token could back 10 more RSV.
+	extra := shiftLeft(1, 18)
+	s.requireTxWithStrictEvents(s.erc20s[0].Transfer(s.signer, s.vaultAddress, extra))(
+		abi.BasicERC20Transfer{From: s.owner.address(), To: s.vaultAddress, Value: extra},
+	)
+	drift, err = s.manager.DriftAfterIssue(nil, bigInt(0))
+	s.Require().NoError(err)
+	s.Equal(shiftLeft(10, 18).String(), drift.String())
+
+	// Issuing more doesn't increase drift.
+	drift, err = s.manager.DriftAfterIssue(nil, rsvAmount)
+	s.Require().NoError(err)
+	s.Equal(shiftLeft(10, 18).String(), drift.String())
+}
+
 // TestIssueIsProtected tests that `issue` reverts when in an emergency or it is paused.
 func (s *ManagerSuite) TestIssueIsProtected() {
 	amount := bigInt(1)
@@ -435,8 +707,9 @@ func (s *ManagerSuite) TestIssueIsProtected() {
 	s.Require().NoError(err)
 	s.Equal(true, paused)
 
-	// Issue should fail now.
+	// Issue should fail now, whoever receives the RSV.
 	s.requireTxFails(s.manager.Issue(signer(s.proposer), amount))
+	s.requireTxFails(s.manager.IssueTo(signer(s.proposer), s.account[5].address(), amount))
 
 	// Unpause issuance.
 	s.requireTxWithStrictEvents(s.manager.SetIssuancePaused(signer(s.operator), false))(
@@ -461,6 +734,169 @@ func (s *ManagerSuite) TestIssueRequireStatements() {
 	s.assertManagerCollateralized()
 }
 
+// TestCanIssue tests that `canIssue` predicts whether `issue` will succeed, and why not.
+func (s *ManagerSuite) TestCanIssue() {
+	amount := shiftLeft(1, 18)
+	expectedAmounts := s.computeExpectedIssueAmounts(bigInt(0), amount)
+
+	// The proposer is funded and approved, so it should be able to issue.
+	ok, reason, err := s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.True(ok)
+	s.Equal("", reason)
+
+	// Zero is never issuable.
+	ok, reason, err = s.manager.CanIssue(nil, s.proposer.address(), bigInt(0))
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("cannot issue zero RSV", reason)
+
+	// An account holding collateral without approving the Manager cannot issue.
+	unapproved := s.account[2]
+	for i, erc20 := range s.erc20s {
+		s.requireTxWithStrictEvents(erc20.Transfer(s.signer, unapproved.address(), expectedAmounts[i]))(
+			abi.BasicERC20Transfer{From: s.owner.address(), To: unapproved.address(), Value: expectedAmounts[i]},
+		)
+	}
+	ok, reason, err = s.manager.CanIssue(nil, unapproved.address(), amount)
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("insufficient collateral allowance", reason)
+	s.requireTxFails(s.manager.Issue(signer(unapproved), amount))
+
+	// An account approving the Manager without holding collateral cannot issue.
+	unfunded := s.account[3]
+	for i, erc20 := range s.erc20s {
+		s.requireTxWithStrictEvents(erc20.Approve(signer(unfunded), s.managerAddress, expectedAmounts[i]))(
+			abi.BasicERC20Approval{Owner: unfunded.address(), Spender: s.managerAddress, Value: expectedAmounts[i]},
+		)
+	}
+	ok, reason, err = s.manager.CanIssue(nil, unfunded.address(), amount)
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("insufficient collateral balance", reason)
+	s.requireTxFails(s.manager.Issue(signer(unfunded), amount))
+
+	// Pause issuance.
+	s.requireTxWithStrictEvents(s.manager.SetIssuancePaused(signer(s.operator), true))(
+		abi.ManagerIssuancePausedChanged{OldVal: false, NewVal: true},
+	)
+	ok, reason, err = s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("issuance is paused", reason)
+	s.requireTxFails(s.manager.Issue(signer(s.proposer), amount))
+
+	// Unpause issuance, and set `emergency` instead.
+	s.requireTxWithStrictEvents(s.manager.SetIssuancePaused(signer(s.operator), false))(
+		abi.ManagerIssuancePausedChanged{OldVal: true, NewVal: false},
+	)
+	s.requireTxWithStrictEvents(s.manager.SetEmergency(signer(s.operator), true))(
+		abi.ManagerEmergencyChanged{OldVal: false, NewVal: true},
+	)
+	ok, reason, err = s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("contract is paused", reason)
+
+	// Clear the emergency; the prediction should hold again.
+	s.requireTxWithStrictEvents(s.manager.SetEmergency(signer(s.operator), false))(
+		abi.ManagerEmergencyChanged{OldVal: true, NewVal: false},
+	)
+	ok, _, err = s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.True(ok)
+
+	// The Reserve's own limits on minting also prevent issuance.
+	s.requireTxWithStrictEvents(s.reserve.ChangePauser(s.signer, s.owner.address()))(
+		abi.ReservePauserChanged{NewPauser: s.owner.address()},
+	)
+	s.requireTxWithStrictEvents(s.reserve.PauseMint(s.signer))(
+		abi.ReserveMintingPaused{Account: s.owner.address()},
+	)
+	ok, reason, err = s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("minting is paused", reason)
+	s.requireTxFails(s.manager.Issue(signer(s.proposer), amount))
+	s.requireTx(s.reserve.UnpauseMint(s.signer))
+
+	s.requireTxWithStrictEvents(s.reserve.Pause(s.signer))(
+		abi.ReservePaused{Account: s.owner.address()},
+	)
+	ok, reason, err = s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("RSV is paused", reason)
+	s.requireTxFails(s.manager.Issue(signer(s.proposer), amount))
+	s.requireTx(s.reserve.Unpause(s.signer))
+
+	s.requireTx(s.reserve.SetMaxMintPerTx(s.signer, new(big.Int).Sub(amount, bigInt(1))))
+	ok, reason, err = s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("mint amount exceeds max", reason)
+	s.requireTxFails(s.manager.Issue(signer(s.proposer), amount))
+	s.requireTx(s.reserve.SetMaxMintPerTx(s.signer, bigInt(0)))
+
+	// The supply must stay strictly below the max, so issuing exactly up to it fails.
+	supply, err := s.reserve.TotalSupply(nil)
+	s.Require().NoError(err)
+	maxSupply := new(big.Int).Add(supply, amount)
+	s.requireTx(s.reserve.ChangeMaxSupply(s.signer, maxSupply))
+	ok, reason, err = s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.False(ok)
+	s.Equal("max supply exceeded", reason)
+	s.requireTxFails(s.manager.Issue(signer(s.proposer), amount))
+	s.requireTx(s.reserve.ChangeMaxSupply(s.signer, maxSupply.Add(maxSupply, bigInt(1))))
+
+	ok, _, err = s.manager.CanIssue(nil, s.proposer.address(), amount)
+	s.Require().NoError(err)
+	s.True(ok)
+	s.requireTx(s.manager.Issue(signer(s.proposer), amount))
+	s.assertRSVBalance(s.proposer.address(), amount)
+}
+
+// TestCollateralAllowanceIncreaseDecrease tests that a collateral holder can adjust the
+// Manager's allowance incrementally, and that issuance respects the adjusted allowance.
+func (s *ManagerSuite) TestCollateralAllowanceIncreaseDecrease() {
+	holder := s.account[2]
+	erc20 := s.erc20s[0]
+	amount := shiftLeft(1, 18)
+
+	// Fund holder with some of the first collateral token.
+	s.requireTxWithStrictEvents(erc20.Transfer(s.signer, holder.address(), amount))(
+		abi.BasicERC20Transfer{From: s.owner.address(), To: holder.address(), Value: amount},
+	)
+	s.assertERC20Allowance(erc20, holder.address(), s.managerAddress, bigInt(0))
+
+	// Increase the allowance twice.
+	s.requireTxWithStrictEvents(erc20.IncreaseAllowance(signer(holder), s.managerAddress, amount))(
+		abi.BasicERC20Approval{Owner: holder.address(), Spender: s.managerAddress, Value: amount},
+	)
+	s.assertERC20Allowance(erc20, holder.address(), s.managerAddress, amount)
+
+	doubled := new(big.Int).Mul(amount, bigInt(2))
+	s.requireTxWithStrictEvents(erc20.IncreaseAllowance(signer(holder), s.managerAddress, amount))(
+		abi.BasicERC20Approval{Owner: holder.address(), Spender: s.managerAddress, Value: doubled},
+	)
+	s.assertERC20Allowance(erc20, holder.address(), s.managerAddress, doubled)
+
+	// Decrease it back down.
+	s.requireTxWithStrictEvents(erc20.DecreaseAllowance(signer(holder), s.managerAddress, amount))(
+		abi.BasicERC20Approval{Owner: holder.address(), Spender: s.managerAddress, Value: amount},
+	)
+	s.assertERC20Allowance(erc20, holder.address(), s.managerAddress, amount)
+
+	// Decreasing below zero should fail and leave the allowance untouched.
+	s.requireTxFails(erc20.DecreaseAllowance(signer(holder), s.managerAddress, doubled))
+	s.assertERC20Allowance(erc20, holder.address(), s.managerAddress, amount)
+
+	// The other allowances are still zero, so issuance should fail.
+	s.requireTxFails(s.manager.Issue(signer(holder), bigInt(1)))
+}
+
 // TestRedeem tests that `redeem` compensates the person with the correct amounts.
 func (s *ManagerSuite) TestRedeem() {
 	// Issue.
@@ -490,6 +926,372 @@ func (s *ManagerSuite) TestRedeem() {
 	s.assertManagerCollateralized()
 }
 
+// TestRedeemWithFee tests that redeeming pays out the collateral less the redeem fee, and that
+// the Vault keeps the difference. With no fee, redemption pays out in full.
+func (s *ManagerSuite) TestRedeemWithFee() {
+	rsvAmount := shiftLeft(1, 27) // 1 billion
+	s.requireTx(s.manager.Issue(signer(s.proposer), rsvAmount))
+
+	redeemer := s.account[4]
+	s.requireTx(s.reserve.Transfer(signer(s.proposer), redeemer.address(), rsvAmount))
+	s.requireTx(s.reserve.Approve(signer(redeemer), s.managerAddress, rsvAmount))
+
+	redeemAmount := shiftLeft(1, 26)
+	fullAmounts := s.computeExpectedRedeemAmounts(redeemAmount)
+
+	// A fee of zero changes nothing.
+	s.requireTx(s.manager.Redeem(signer(redeemer), redeemAmount))
+	for i, erc20 := range s.erc20s {
+		s.assertERC20Balance(erc20, redeemer.address(), fullAmounts[i])
+	}
+
+	// With a 1% fee, the redeemer receives 99% of the collateral, and the Vault keeps the rest.
+	s.requireTx(s.manager.SetRedeemFee(s.signer, bigInt(100)))
+	vaultBefore := make([]*big.Int, len(s.erc20s))
+	for i, erc20 := range s.erc20s {
+		balance, err := erc20.BalanceOf(nil, s.vaultAddress)
+		s.Require().NoError(err)
+		vaultBefore[i] = balance
+	}
+
+	s.requireTx(s.manager.Redeem(signer(redeemer), redeemAmount))(
+		abi.ManagerRedemption{User: redeemer.address(), Amount: redeemAmount},
+		abi.ManagerRedeemFee{
+			User: redeemer.address(), RsvAmount: redeemAmount, Fee: bigInt(0).Div(redeemAmount, bigInt(100)),
+		},
+	)
+	for i, erc20 := range s.erc20s {
+		fee := bigInt(0).Div(fullAmounts[i], bigInt(100))
+		paid := bigInt(0).Sub(fullAmounts[i], fee)
+		s.assertERC20Balance(erc20, redeemer.address(), bigInt(0).Add(fullAmounts[i], paid))
+		s.assertERC20Balance(erc20, s.vaultAddress, bigInt(0).Sub(vaultBefore[i], paid))
+	}
+
+	s.assertManagerCollateralized()
+}
+
+// TestRedeemMin tests that redeemMin redeems when every payout meets its minimum, and reverts
+// when any doesn't.
+func (s *ManagerSuite) TestRedeemMin() {
+	rsvAmount := shiftLeft(1, 27) // 1 billion
+	s.requireTx(s.manager.Issue(signer(s.proposer), rsvAmount))
+
+	redeemer := s.account[4]
+	s.requireTx(s.reserve.Transfer(signer(s.proposer), redeemer.address(), rsvAmount))
+	s.requireTx(s.reserve.Approve(signer(redeemer), s.managerAddress, rsvAmount))
+
+	half := bigInt(0).Div(rsvAmount, bigInt(2))
+	amounts := s.computeExpectedRedeemAmounts(half)
+
+	// Asking for one qToken too many of any token reverts.
+	for i := range amounts {
+		inflated := make([]*big.Int, len(amounts))
+		copy(inflated, amounts)
+		inflated[i] = bigInt(0).Add(amounts[i], bigInt(1))
+		s.requireTxRevertsWith(s.manager.RedeemMin(withGasLimit(signer(redeemer), 1e6), half, inflated))(
+			"redemption below minimum",
+		)
+	}
+
+	// So does giving the wrong number of minimums.
+	s.requireTxFails(s.manager.RedeemMin(signer(redeemer), half, amounts[:len(amounts)-1]))
+	s.assertRSVBalance(redeemer.address(), rsvAmount)
+
+	// Exact minimums succeed.
+	s.requireTx(s.manager.RedeemMin(signer(redeemer), half, amounts))(
+		abi.ManagerRedemption{User: redeemer.address(), Amount: half},
+	)
+	for i, erc20 := range s.erc20s {
+		s.assertERC20Balance(erc20, redeemer.address(), amounts[i])
+	}
+	s.assertRSVBalance(redeemer.address(), half)
+	s.assertManagerCollateralized()
+}
+
+// TestMaxRedeemable tests that `maxRedeemable` is bounded by RSV balance, RSV allowance,
+// and the availability of collateral in the Vault.
+func (s *ManagerSuite) TestMaxRedeemable() {
+	redeemer := s.proposer
+	rsvAmount := shiftLeft(1, 21)
+	s.requireTx(s.manager.Issue(signer(redeemer), rsvAmount))
+
+	// Nothing is redeemable without an allowance.
+	max, err := s.manager.MaxRedeemable(nil, redeemer.address())
+	s.Require().NoError(err)
+	s.Equal("0", max.String())
+
+	// With a partial allowance, the allowance is the bound.
+	half := bigInt(0).Div(rsvAmount, bigInt(2))
+	s.requireTx(s.reserve.Approve(signer(redeemer), s.managerAddress, half))
+	max, err = s.manager.MaxRedeemable(nil, redeemer.address())
+	s.Require().NoError(err)
+	s.Equal(half.String(), max.String())
+
+	// With an ample allowance, the balance is the bound.
+	s.requireTx(s.reserve.Approve(signer(redeemer), s.managerAddress, maxUint256()))
+	max, err = s.manager.MaxRedeemable(nil, redeemer.address())
+	s.Require().NoError(err)
+	s.Equal(rsvAmount.String(), max.String())
+
+	// While RSV is paused, nothing is redeemable, and redemption indeed fails.
+	s.requireTx(s.reserve.ChangePauser(s.signer, s.owner.address()))
+	s.requireTx(s.reserve.Pause(s.signer))
+	max, err = s.manager.MaxRedeemable(nil, redeemer.address())
+	s.Require().NoError(err)
+	s.Equal("0", max.String())
+	s.requireTxFails(s.manager.Redeem(signer(redeemer), bigInt(1)))
+	s.requireTx(s.reserve.Unpause(s.signer))
+
+	max, err = s.manager.MaxRedeemable(nil, redeemer.address())
+	s.Require().NoError(err)
+	s.Equal(rsvAmount.String(), max.String())
+
+	// Take some of the first collateral token out of the Vault, leaving it short.
+	s.requireTx(s.vault.ChangeManager(s.signer, s.owner.address()))
+	s.requireTx(s.vault.WithdrawTo(s.signer, s.erc20Addresses[0], bigInt(1), s.owner.address()))
+	s.requireTx(s.vault.ChangeManager(s.signer, s.managerAddress))
+
+	s.assertManagerUndercollateralized()
+
+	// Now nothing is redeemable, and redemption indeed fails.
+	max, err = s.manager.MaxRedeemable(nil, redeemer.address())
+	s.Require().NoError(err)
+	s.Equal("0", max.String())
+	s.requireTxFails(s.manager.Redeem(signer(redeemer), bigInt(1)))
+}
+
+// TestUndercollateralizedAfterLoss tests that the Manager reports undercollateralization once
+// the Vault loses some of its collateral, and that issuance stops.
+func (s *ManagerSuite) TestUndercollateralizedAfterLoss() {
+	rsvAmount := shiftLeft(1, 21)
+	s.requireTx(s.manager.Issue(signer(s.proposer), rsvAmount))
+	s.assertManagerCollateralized()
+
+	// Simulate a loss of each collateral token in turn.
+	for i, erc20Address := range s.erc20Addresses {
+		s.requireTx(s.vault.ChangeManager(s.signer, s.owner.address()))
+		s.requireTx(s.vault.WithdrawTo(s.signer, erc20Address, bigInt(1), s.owner.address()))
+		s.requireTx(s.vault.ChangeManager(s.signer, s.managerAddress))
+		s.assertManagerUndercollateralized()
+		s.requireTxFails(s.manager.Issue(signer(s.proposer), bigInt(1)))
+
+		// Restore the loss.
+		s.requireTxWithStrictEvents(s.erc20s[i].Transfer(s.signer, s.vaultAddress, bigInt(1)))(
+			abi.BasicERC20Transfer{From: s.owner.address(), To: s.vaultAddress, Value: bigInt(1)},
+		)
+		s.assertManagerCollateralized()
+	}
+}
+
+// TestOracleCollateralization tests that, with an oracle set, the Manager values the Vault's
+// holdings at the oracle's prices, and so becomes undercollateralized when a token's price drops.
+// It also tests that prices above full value are capped, and that an oracle missing a price for a
+// basket token can't be set.
+func (s *ManagerSuite) TestOracleCollateralization() {
+	s.requireTx(s.manager.Issue(signer(s.proposer), shiftLeft(1, 21)))
+
+	oracleAddress, tx, oracle, err := abi.DeployOracle(s.signer, s.node)
+	s.logParsers[oracleAddress] = oracle
+	s.requireTx(tx, err)
+
+	// Every token at full value. The oracle can't be accepted until it prices the whole basket.
+	s.requireTx(s.manager.ProposeOracle(s.signer, oracleAddress))
+	s.adjustTime(24 * time.Hour)
+	fullPrice := shiftLeft(1, 18)
+	for i, erc20Address := range s.erc20Addresses {
+		if i == len(s.erc20Addresses)-1 {
+			s.requireTxRevertsWith(s.manager.AcceptOracle(withGasLimit(s.signer, 1e6)))(
+				"oracle must price every basket token",
+			)
+		}
+		s.requireTxWithStrictEvents(oracle.SetPrice(s.signer, erc20Address, fullPrice))(
+			abi.OraclePriceChanged{Token: erc20Address, OldPrice: bigInt(0), NewPrice: fullPrice},
+		)
+	}
+	s.requireTxWithStrictEvents(s.manager.AcceptOracle(s.signer))(
+		abi.ManagerOracleChanged{OldOracleAddr: zeroAddress(), NewOracleAddr: oracleAddress},
+	)
+	trustedOracle, err := s.manager.TrustedOracle(nil)
+	s.Require().NoError(err)
+	s.Equal(oracleAddress, trustedOracle)
+	s.assertManagerCollateralized()
+
+	// One token drops to 90% of its value.
+	s.requireTx(oracle.SetPrice(s.signer, s.erc20Addresses[1], shiftLeft(9, 17)))
+	s.assertManagerUndercollateralized()
+	s.requireTxFails(s.manager.Issue(signer(s.proposer), bigInt(1)))
+
+	// It recovers.
+	s.requireTx(oracle.SetPrice(s.signer, s.erc20Addresses[1], fullPrice))
+	s.assertManagerCollateralized()
+
+	// Without an oracle, prices are ignored.
+	s.requireTx(oracle.SetPrice(s.signer, s.erc20Addresses[1], bigInt(0)))
+	s.assertManagerUndercollateralized()
+	s.requireTx(s.manager.ProposeOracle(s.signer, zeroAddress()))
+	s.adjustTime(24 * time.Hour)
+	s.requireTxWithStrictEvents(s.manager.AcceptOracle(s.signer))(
+		abi.ManagerOracleChanged{OldOracleAddr: oracleAddress, NewOracleAddr: zeroAddress()},
+	)
+	s.assertManagerCollateralized()
+
+	// Mint RSV without collateral, leaving the Vault short of every token.
+	s.requireTx(s.reserve.ChangeMinter(s.signer, s.owner.address()))
+	s.requireTx(s.reserve.Mint(s.signer, s.owner.address(), shiftLeft(1, 19)))
+	s.assertManagerUndercollateralized()
+
+	// Prices above full value count as full value, so they don't cover the shortfall.
+	for _, erc20Address := range s.erc20Addresses {
+		s.requireTx(oracle.SetPrice(s.signer, erc20Address, shiftLeft(2, 18)))
+	}
+	s.setOracle(oracleAddress)
+	s.assertManagerUndercollateralized()
+}
+
+// TestRedeemAfterPriceDrop tests that, while a token trades below full value, issuance stops but
+// holders can still redeem, and the basket can still be rebalanced.
+func (s *ManagerSuite) TestRedeemAfterPriceDrop() {
+	rsvAmount := shiftLeft(1, 21)
+	s.requireTx(s.manager.Issue(signer(s.proposer), rsvAmount))
+	s.requireTx(s.reserve.Approve(signer(s.proposer), s.managerAddress, rsvAmount))
+
+	oracleAddress, tx, oracle, err := abi.DeployOracle(s.signer, s.node)
+	s.logParsers[oracleAddress] = oracle
+	s.requireTx(tx, err)
+	for _, erc20Address := range s.erc20Addresses {
+		s.requireTx(oracle.SetPrice(s.signer, erc20Address, shiftLeft(1, 18)))
+	}
+	s.setOracle(oracleAddress)
+
+	// One token drops, just barely, below full value.
+	s.requireTx(oracle.SetPrice(s.signer, s.erc20Addresses[1], new(big.Int).Sub(shiftLeft(1, 18), bigInt(1))))
+	s.assertManagerUndercollateralized()
+	s.requireTxRevertsWith(s.manager.Issue(withGasLimit(signer(s.proposer), 1e6), bigInt(1)))(
+		"undercollateralized",
+	)
+
+	// Holders can still redeem, and see that they can.
+	maxRedeemable, err := s.manager.MaxRedeemable(nil, s.proposer.address())
+	s.Require().NoError(err)
+	s.Equal(rsvAmount.String(), maxRedeemable.String())
+	half := new(big.Int).Div(rsvAmount, bigInt(2))
+	s.requireTx(s.manager.Redeem(signer(s.proposer), half))(
+		abi.ManagerRedemption{User: s.proposer.address(), Amount: half},
+	)
+	s.assertRSVBalance(s.proposer.address(), half)
+
+	// And proposals can still be made.
+	s.requireTx(s.manager.ProposeWeights(signer(s.proposer), s.erc20Addresses, s.weights))
+}
+
+// TestExecuteProposalRequiresPrices tests that a proposal can't be executed if the oracle doesn't
+// price every token in the basket it would produce.
+func (s *ManagerSuite) TestExecuteProposalRequiresPrices() {
+	s.requireTx(s.manager.Issue(signer(s.proposer), shiftLeft(1, 21)))
+
+	oracleAddress, tx, oracle, err := abi.DeployOracle(s.signer, s.node)
+	s.logParsers[oracleAddress] = oracle
+	s.requireTx(tx, err)
+	for _, erc20Address := range s.erc20Addresses {
+		s.requireTx(oracle.SetPrice(s.signer, erc20Address, shiftLeft(1, 18)))
+	}
+	s.setOracle(oracleAddress)
+
+	// Propose adding a token the oracle doesn't price.
+	newTokenAddress, tx, newToken, err := abi.DeployBasicERC20(signer(s.proposer), s.node, "New Token", "NEW", 18, s.chainID)
+	s.logParsers[newTokenAddress] = newToken
+	s.requireTx(tx, err)
+	s.requireTx(newToken.Approve(signer(s.proposer), s.managerAddress, shiftLeft(1, 48)))
+	tokens := append(append([]common.Address{}, s.erc20Addresses...), newTokenAddress)
+	weights := append(append([]*big.Int{}, s.weights...), shiftLeft(1, 17))
+	s.requireTx(s.manager.ProposeWeights(signer(s.proposer), tokens, weights))
+	s.requireTx(s.manager.AcceptProposal(signer(s.operator), bigInt(1)))
+	s.adjustTime(24 * time.Hour)
+
+	s.requireTxRevertsWith(s.manager.ExecuteProposal(withGasLimit(signer(s.operator), 5e6), bigInt(1)))(
+		"oracle must price every basket token",
+	)
+
+	// Once it's priced, the proposal executes.
+	s.requireTx(oracle.SetPrice(s.signer, newTokenAddress, shiftLeft(1, 18)))
+	s.requireTx(s.manager.ExecuteProposal(signer(s.operator), bigInt(1)))
+	s.assertManagerCollateralized()
+}
+
+// TestOracleTimelock tests that a proposed oracle can't be accepted before the delay, that the old
+// oracle stays in use until it is, and that the new one takes over on acceptance.
+func (s *ManagerSuite) TestOracleTimelock() {
+	s.requireTx(s.manager.Issue(signer(s.proposer), shiftLeft(1, 21)))
+
+	deployOracle := func(price *big.Int) (common.Address, *abi.Oracle) {
+		address, tx, oracle, err := abi.DeployOracle(s.signer, s.node)
+		s.logParsers[address] = oracle
+		s.requireTx(tx, err)
+		for _, erc20Address := range s.erc20Addresses {
+			s.requireTx(oracle.SetPrice(s.signer, erc20Address, price))
+		}
+		return address, oracle
+	}
+	oldOracleAddress, _ := deployOracle(shiftLeft(1, 18))
+	newOracleAddress, _ := deployOracle(shiftLeft(9, 17))
+	s.setOracle(oldOracleAddress)
+
+	// Nothing is proposed yet.
+	s.requireTxRevertsWith(s.manager.AcceptOracle(withGasLimit(s.signer, 1e6)))("no oracle proposed")
+
+	tx, err := s.manager.ProposeOracle(s.signer, newOracleAddress)
+	s.requireTx(tx, err)
+	readyAt, err := s.manager.OracleReadyAt(nil)
+	s.Require().NoError(err)
+	s.requireTxWithStrictEvents(tx, nil)(
+		abi.ManagerOracleProposed{NewOracleAddr: newOracleAddress, ReadyAt: readyAt},
+	)
+	proposed, err := s.manager.ProposedOracle(nil)
+	s.Require().NoError(err)
+	s.Equal(newOracleAddress, proposed)
+
+	// Until the delay is over, the new oracle can't be accepted, and the old one values the Vault.
+	s.adjustTime(23 * time.Hour)
+	s.requireTxRevertsWith(s.manager.AcceptOracle(withGasLimit(s.signer, 1e6)))("oracle delay not over")
+	trustedOracle, err := s.manager.TrustedOracle(nil)
+	s.Require().NoError(err)
+	s.Equal(oldOracleAddress, trustedOracle)
+	s.assertManagerCollateralized()
+
+	// After the delay, accepting switches to the new oracle's prices.
+	s.adjustTime(1 * time.Hour)
+	s.requireTxWithStrictEvents(s.manager.AcceptOracle(s.signer))(
+		abi.ManagerOracleChanged{OldOracleAddr: oldOracleAddress, NewOracleAddr: newOracleAddress},
+	)
+	trustedOracle, err = s.manager.TrustedOracle(nil)
+	s.Require().NoError(err)
+	s.Equal(newOracleAddress, trustedOracle)
+	s.assertManagerUndercollateralized()
+
+	// The proposal is used up.
+	s.requireTxRevertsWith(s.manager.AcceptOracle(withGasLimit(s.signer, 1e6)))("no oracle proposed")
+}
+
+// TestOracleIsProtected tests that only the owner can propose or accept an oracle.
+func (s *ManagerSuite) TestOracleIsProtected() {
+	s.requireTxFails(s.manager.ProposeOracle(signer(s.account[2]), s.account[3].address()))
+	s.requireTxFails(s.manager.ProposeOracle(signer(s.operator), s.account[3].address()))
+
+	s.requireTx(s.manager.ProposeOracle(s.signer, zeroAddress()))
+	s.adjustTime(24 * time.Hour)
+	s.requireTxFails(s.manager.AcceptOracle(signer(s.account[2])))
+	s.requireTxFails(s.manager.AcceptOracle(signer(s.operator)))
+}
+
+// setOracle proposes `oracle` as the Manager's price oracle, waits out the delay, and accepts it.
+func (s *ManagerSuite) setOracle(oracle common.Address) {
+	s.requireTx(s.manager.ProposeOracle(s.signer, oracle))
+	delay, err := s.manager.Delay(nil)
+	s.Require().NoError(err)
+	s.adjustTime(time.Duration(delay.Int64()) * time.Second)
+	s.requireTx(s.manager.AcceptOracle(s.signer))
+}
+
 // TestRedeemIsProtected tests that `redeem` compensates the person with the correct amounts.
 func (s *ManagerSuite) TestRedeemIsProtected() {
 	// Issue.
@@ -559,6 +1361,41 @@ func (s *ManagerSuite) TestRedeemRequireStatements() {
 	s.assertManagerCollateralized()
 }
 
+// TestBasketUnchangedUntilDelay tests that an accepted weight proposal leaves the active basket
+// untouched until the proposal delay has passed and the proposal is executed.
+func (s *ManagerSuite) TestBasketUnchangedUntilDelay() {
+	oldBasketAddress, err := s.manager.TrustedBasket(nil)
+	s.Require().NoError(err)
+	oldBasket, err := abi.NewBasket(oldBasketAddress, s.node)
+	s.Require().NoError(err)
+
+	delay, err := s.manager.Delay(nil)
+	s.Require().NoError(err)
+	s.Equal(bigInt(24*60*60).String(), delay.String())
+
+	newWeights := []*big.Int{shiftLeft(2, 35), shiftLeft(3, 35), shiftLeft(5, 35)}
+	s.requireTx(s.manager.ProposeWeights(signer(s.proposer), s.erc20Addresses, newWeights))
+	s.requireTx(s.manager.AcceptProposal(signer(s.operator), bigInt(1)))
+	s.assertBasket(oldBasket, s.erc20Addresses, s.weights)
+
+	// Just short of the delay, the proposal can't be executed, and the basket is unchanged.
+	s.adjustTime(23 * time.Hour)
+	s.requireTxFails(s.manager.ExecuteProposal(signer(s.operator), bigInt(1)))
+	s.assertBasket(oldBasket, s.erc20Addresses, s.weights)
+
+	// After the delay, executing the proposal switches to the new basket.
+	s.adjustTime(1 * time.Hour)
+	s.requireTx(s.manager.ExecuteProposal(signer(s.operator), bigInt(1)))
+
+	newBasketAddress, err := s.manager.TrustedBasket(nil)
+	s.Require().NoError(err)
+	s.NotEqual(oldBasketAddress, newBasketAddress)
+	newBasket, err := abi.NewBasket(newBasketAddress, s.node)
+	s.Require().NoError(err)
+	s.assertBasket(newBasket, s.erc20Addresses, newWeights)
+	s.assertManagerCollateralized()
+}
+
 // TestProposeWeightsUseCase sets a basket, issues RSV, changes the basket, and redeems RSV.
 func (s *ManagerSuite) TestProposeWeightsFullUsecase() {
 	// Issue a billion RSV.
@@ -584,6 +1421,22 @@ func (s *ManagerSuite) TestProposeWeightsFullUsecase() {
 
 }
 
+// TestRebalanceOnBasketChange issues RSV, changes to a basket with different weights, and checks
+// that executing the proposal rebalances the Vault's collateral to the new weights.
+func (s *ManagerSuite) TestRebalanceOnBasketChange() {
+	rsvToIssue := shiftLeft(1, 27) // 1 billion
+	s.requireTx(s.manager.Issue(signer(s.proposer), rsvToIssue))
+	s.assertVaultCollateralComposition()
+
+	// Move weight from the first token to the other two.
+	newWeights := []*big.Int{shiftLeft(1, 35), shiftLeft(5, 35), shiftLeft(4, 35)}
+	s.changeBasketUsingWeightProposal(s.erc20Addresses, newWeights)
+
+	s.assertBasket(s.basket, s.erc20Addresses, newWeights)
+	s.assertVaultCollateralComposition()
+	s.assertManagerCollateralized()
+}
+
 // TestProposeSwapFullUsecase sets up a basket with a WeightProposal, issues RSV,
 // changes the basket using a SwapProposal, and redeems the RSV.
 func (s *ManagerSuite) TestProposeSwapFullUsecase() {
@@ -639,7 +1492,7 @@ func (s *ManagerSuite) TestAddTokenUsecase() {
 
 	// Change Basket
 
-	newTokenAddr, _, _, err := abi.DeployBasicERC20(s.signer, s.node)
+	newTokenAddr, _, _, err := abi.DeployBasicERC20(s.signer, s.node, "Basic Token", "BSC", 18, s.chainID)
 	newTokenAddrs := append(s.erc20Addresses, newTokenAddr)
 	//fmt.Println(newTokenAddrs)
 	newWeights := []*big.Int{shiftLeft(1, 35), shiftLeft(2, 35), shiftLeft(3, 35), shiftLeft(4, 35)}
@@ -0,0 +1,157 @@
+// +build all
+
+package tests
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/reserve-protocol/rsv-beta/abi"
+)
+
+func TestMerkleClaim(t *testing.T) {
+	runSuite(t, new(MerkleClaimSuite))
+}
+
+type MerkleClaimSuite struct {
+	TestSuite
+
+	merkleClaim        *abi.MerkleClaim
+	merkleClaimAddress common.Address
+}
+
+var (
+	// Compile-time check that MerkleClaimSuite implements the interfaces we think it does.
+	// If it does not implement these interfaces, then the corresponding setup and teardown
+	// functions will not actually run.
+	_ suite.BeforeTest       = &MerkleClaimSuite{}
+	_ suite.SetupAllSuite    = &MerkleClaimSuite{}
+	_ suite.TearDownAllSuite = &MerkleClaimSuite{}
+)
+
+// SetupSuite runs once, before all of the tests in the suite.
+func (s *MerkleClaimSuite) SetupSuite() {
+	s.setup()
+}
+
+// BeforeTest runs before each test in the suite.
+func (s *MerkleClaimSuite) BeforeTest(suiteName, testName string) {
+	s.owner = s.account[0]
+
+	// Reserve, unpaused, with the deployer as minter.
+	reserveAddress, tx, reserve, err := abi.DeployReserve(s.signer, s.node)
+	s.logParsers = map[common.Address]logParser{
+		reserveAddress: reserve,
+	}
+	s.requireTx(tx, err)
+	s.requireTx(reserve.Unpause(s.signer))
+	s.requireTx(reserve.ChangeMinter(s.signer, s.owner.address()))
+	s.reserve = reserve
+	s.reserveAddress = reserveAddress
+
+	// MerkleClaim, funded with RSV.
+	merkleClaimAddress, tx, merkleClaim, err := abi.DeployMerkleClaim(s.signer, s.node, reserveAddress)
+	s.logParsers[merkleClaimAddress] = merkleClaim
+	s.requireTxWithStrictEvents(tx, err)(
+		abi.MerkleClaimOwnershipTransferred{
+			PreviousOwner: zeroAddress(), NewOwner: s.owner.address(),
+		},
+	)
+	s.merkleClaim = merkleClaim
+	s.merkleClaimAddress = merkleClaimAddress
+
+	s.requireTx(s.reserve.Mint(s.signer, merkleClaimAddress, shiftLeft(1, 21)))
+}
+
+// TestClaim builds a tree of claims, and has two accounts claim from it.
+func (s *MerkleClaimSuite) TestClaim() {
+	accounts := []common.Address{
+		s.account[1].address(), s.account[2].address(), s.account[3].address(),
+	}
+	amounts := []*big.Int{shiftLeft(1, 18), shiftLeft(25, 17), shiftLeft(7, 18)}
+	leaves := make([][]byte, len(accounts))
+	for i := range accounts {
+		leaves[i] = merkleLeaf(accounts[i], amounts[i])
+	}
+	root, proofs := merkleTree(leaves)
+
+	s.requireTxWithStrictEvents(s.merkleClaim.SetMerkleRoot(s.signer, root))(
+		abi.MerkleClaimMerkleRootChanged{OldRoot: [32]byte{}, NewRoot: root},
+	)
+
+	for _, i := range []int{0, 2} {
+		claimer := s.account[i+1]
+		s.requireTxWithStrictEvents(s.merkleClaim.Claim(signer(claimer), amounts[i], proofs[i]))(
+			abi.MerkleClaimClaimed{Account: accounts[i], Amount: amounts[i]},
+			abi.ReserveTransfer{From: s.merkleClaimAddress, To: accounts[i], Value: amounts[i]},
+		)
+		s.assertRSVBalance(accounts[i], amounts[i])
+
+		claimed, err := s.merkleClaim.Claimed(nil, accounts[i])
+		s.Require().NoError(err)
+		s.True(claimed)
+
+		// A claim can only be made once.
+		s.requireTxFails(s.merkleClaim.Claim(signer(claimer), amounts[i], proofs[i]))
+	}
+
+	// Bad claims fail: the wrong amount, someone else's proof, or a claim not in the tree.
+	s.requireTxRevertsWith(s.merkleClaim.Claim(
+		withGasLimit(signer(s.account[2]), 1e6), amounts[0], proofs[1],
+	))("invalid proof")
+	s.requireTxFails(s.merkleClaim.Claim(signer(s.account[2]), amounts[1], proofs[0]))
+	s.requireTxFails(s.merkleClaim.Claim(signer(s.account[4]), amounts[1], proofs[1]))
+	s.assertRSVBalance(accounts[1], bigInt(0))
+}
+
+// TestSetMerkleRootIsProtected tests that only the owner can set the root.
+func (s *MerkleClaimSuite) TestSetMerkleRootIsProtected() {
+	s.requireTxFails(s.merkleClaim.SetMerkleRoot(signer(s.account[1]), [32]byte{1}))
+}
+
+// merkleLeaf returns the MerkleClaim leaf for a claim of `amount` by `account`.
+func merkleLeaf(account common.Address, amount *big.Int) []byte {
+	return crypto.Keccak256(account.Bytes(), common.LeftPadBytes(amount.Bytes(), 32))
+}
+
+// merkleTree builds a Merkle tree over `leaves` the way MerkleClaim verifies it, and returns its
+// root and the proof for each leaf. A node without a sibling moves up a level unchanged.
+func merkleTree(leaves [][]byte) (root [32]byte, proofs [][][32]byte) {
+	proofs = make([][][32]byte, len(leaves))
+	// position[i] is the index of leaf i's ancestor in the current level.
+	position := make([]int, len(leaves))
+	for i := range position {
+		position[i] = i
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		for i, p := range position {
+			if sibling := p ^ 1; sibling < len(level) {
+				var hash [32]byte
+				copy(hash[:], level[sibling])
+				proofs[i] = append(proofs[i], hash)
+			}
+			position[i] = p / 2
+		}
+
+		var next [][]byte
+		for j := 0; j < len(level); j += 2 {
+			if j+1 == len(level) {
+				next = append(next, level[j])
+			} else if bytes.Compare(level[j], level[j+1]) <= 0 {
+				next = append(next, crypto.Keccak256(level[j], level[j+1]))
+			} else {
+				next = append(next, crypto.Keccak256(level[j+1], level[j]))
+			}
+		}
+		level = next
+	}
+	copy(root[:], level[0])
+	return root, proofs
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func TestOwnable(t *testing.T) {
-	suite.Run(t, new(OwnableSuite))
+	runSuite(t, new(OwnableSuite))
 }
 
 type OwnableSuite struct {
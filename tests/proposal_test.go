@@ -13,9 +13,12 @@ import (
 	"github.com/reserve-protocol/rsv-beta/abi"
 )
 
-func TestProposal(t *testing.T) {
-	suite.Run(t, new(WeightProposalSuite))
-	suite.Run(t, new(SwapProposalSuite))
+func TestWeightProposal(t *testing.T) {
+	runSuite(t, new(WeightProposalSuite))
+}
+
+func TestSwapProposal(t *testing.T) {
+	runSuite(t, new(SwapProposalSuite))
 }
 
 type WeightProposalSuite struct {
@@ -72,16 +75,10 @@ func (s *WeightProposalSuite) BeforeTest(suiteName, testName string) {
 	s.logParsers = map[common.Address]logParser{}
 
 	// Deploy collateral ERC20s for a basket.
-	s.erc20s = make([]*abi.BasicERC20, 3)
-	s.erc20Addresses = make([]common.Address, 3)
+	s.deployERC20s(erc20Specs(18, 18, 18))
 	s.weights = make([]*big.Int, 3)
 	for i := 0; i < 3; i++ {
-		erc20Address, _, erc20, err := abi.DeployBasicERC20(s.signer, s.node)
-		s.Require().NoError(err)
-		s.erc20s[i] = erc20
-		s.erc20Addresses[i] = erc20Address
 		s.weights[i] = bigInt(uint32(i + 1))
-		s.logParsers[erc20Address] = erc20
 	}
 
 	// Make a non-empty basket
@@ -334,7 +331,7 @@ func (s *WeightProposalSuite) TestCompleteCannotHappenBeforeNow() {
 	s.requireTxFails(s.proposal.Complete(s.signer, s.reserveAddress, s.basketAddress))
 
 	// Advance the time.
-	s.node.(backend).AdjustTime(100 * time.Second)
+	s.adjustTime(100 * time.Second)
 
 	// Now the proposal can be completed.
 	s.requireTxWithStrictEvents(s.proposal.Complete(s.signer, s.reserveAddress, s.basketAddress))(
@@ -429,16 +426,10 @@ func (s *SwapProposalSuite) BeforeTest(suiteName, testName string) {
 	)
 
 	// Deploy collateral ERC20s
-	s.erc20s = make([]*abi.BasicERC20, 3)
-	s.erc20Addresses = make([]common.Address, 3)
+	s.deployERC20s(erc20Specs(18, 18, 18))
 	s.weights = make([]*big.Int, 3)
 	for i := 0; i < 3; i++ {
-		erc20Address, _, erc20, err := abi.DeployBasicERC20(s.signer, s.node)
-		s.Require().NoError(err)
-		s.erc20s[i] = erc20
-		s.erc20Addresses[i] = erc20Address
 		s.weights[i] = bigInt(uint32(i + 1))
-		s.logParsers[erc20Address] = erc20
 	}
 
 	// Finally, deploy a basket.
@@ -651,7 +642,7 @@ func (s *SwapProposalSuite) TestCompleteCannotHappenBeforeNow() {
 	s.requireTxFails(s.proposal.Complete(s.signer, s.reserveAddress, s.basketAddress))
 
 	// Advance the time.
-	s.node.(backend).AdjustTime(100 * time.Second)
+	s.adjustTime(100 * time.Second)
 
 	// Now the proposal can be completed.
 	s.requireTx(s.proposal.Complete(s.signer, s.reserveAddress, s.basketAddress))
@@ -0,0 +1,66 @@
+// +build all
+
+package tests
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToBaseUnits(t *testing.T) {
+	cases := []struct {
+		amount   float64
+		decimals uint32
+		want     string
+	}{
+		{1, 6, "1000000"},
+		{1, 8, "100000000"},
+		{1, 18, "1000000000000000000"},
+		{0.1, 6, "100000"},
+		{0.1, 18, "100000000000000000"},
+		{123.456789, 6, "123456789"},
+		{0.12345678, 8, "12345678"},
+		{1e-18, 18, "1"},
+
+		// Digits past the token's decimals are rounded to the nearest base unit.
+		{1.2345674, 6, "1234567"},
+		{1.2345675, 6, "1234568"},
+		{0.000000015, 8, "2"},
+		{0.000000014, 8, "1"},
+		{1e-19, 18, "0"},
+		{5e-19, 18, "1"},
+		{-1.5e-6, 6, "-2"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, toBaseUnits(c.amount, c.decimals).String(), "toBaseUnits(%v, %v)", c.amount, c.decimals)
+	}
+}
+
+func TestFromBaseUnits(t *testing.T) {
+	cases := []struct {
+		amount   int64
+		decimals uint32
+		want     string
+	}{
+		{1000000, 6, "1"},
+		{1500000, 6, "1.5"},
+		{1, 6, "0.000001"},
+		{100000000, 8, "1"},
+		{12345678, 8, "0.12345678"},
+		{1, 18, "0.000000000000000001"},
+		{0, 18, "0"},
+	}
+	for _, c := range cases {
+		got := fromBaseUnits(big.NewInt(c.amount), c.decimals)
+		assert.Equal(t, c.want, got.Text('f', -1), "fromBaseUnits(%v, %v)", c.amount, c.decimals)
+	}
+
+	// Converting to base units and back is exact, up to the token's decimals.
+	for _, decimals := range []uint32{6, 8, 18} {
+		amount := toBaseUnits(1234.5, decimals)
+		back, _ := fromBaseUnits(amount, decimals).Float64()
+		assert.Equal(t, 1234.5, back)
+	}
+}
@@ -3,20 +3,18 @@
 package tests
 
 import (
-	"fmt"
 	"math/big"
-	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/reserve-protocol/rsv-beta/abi"
-	"github.com/reserve-protocol/rsv-beta/soltools"
 )
 
 func TestVault(t *testing.T) {
-	suite.Run(t, new(VaultSuite))
+	runSuite(t, new(VaultSuite))
 }
 
 type VaultSuite struct {
@@ -39,22 +37,7 @@ func (s *VaultSuite) SetupSuite() {
 
 // TearDownSuite runs once, after all of the tests in the suite.
 func (s *VaultSuite) TearDownSuite() {
-	if coverageEnabled {
-		// Write coverage profile to disk.
-		s.Assert().NoError(s.node.(*soltools.Backend).WriteCoverage())
-
-		// Close the node.js process.
-		s.Assert().NoError(s.node.(*soltools.Backend).Close())
-
-		// Process coverage profile into an HTML report.
-		if out, err := exec.Command("npx", "istanbul", "report", "html").CombinedOutput(); err != nil {
-			fmt.Println()
-			fmt.Println("I generated coverage information in coverage/coverage.json.")
-			fmt.Println("I tried to process it with `istanbul` to turn it into a readable report, but failed.")
-			fmt.Println("The error I got when running istanbul was:", err)
-			fmt.Println("Istanbul's output was:\n" + string(out))
-		}
-	}
+	s.TestSuite.TearDownSuite()
 }
 
 // BeforeTest runs before each test in the suite.
@@ -79,15 +62,8 @@ func (s *VaultSuite) BeforeTest(suiteName, testName string) {
 	s.vaultAddress = vaultAddress
 
 	// Deploy collateral ERC20s
-	s.erc20s = make([]*abi.BasicERC20, 3)
-	s.erc20Addresses = make([]common.Address, 3)
-	for i := 0; i < 3; i++ {
-		erc20Address, _, erc20, err := abi.DeployBasicERC20(s.signer, s.node)
-		s.Require().NoError(err)
-		s.erc20s[i] = erc20
-		s.erc20Addresses[i] = erc20Address
-		s.logParsers[erc20Address] = erc20
-
+	s.deployERC20s(erc20Specs(18, 18, 18))
+	for _, erc20 := range s.erc20s {
 		val := bigInt(1000)
 		s.requireTxWithStrictEvents(erc20.Transfer(s.signer, vaultAddress, val))(
 			abi.BasicERC20Transfer{
@@ -258,6 +234,65 @@ func (s *VaultSuite) TestWithdrawToProtected() {
 	)
 }
 
+// TestEmergencyWithdraw tests that the owner can withdraw directly, but only once the timelock on
+// a matching request has passed.
+func (s *VaultSuite) TestEmergencyWithdraw() {
+	receiver := s.account[2]
+	token := s.erc20Addresses[0]
+	amount := bigInt(300)
+
+	// Withdrawing without a request fails.
+	s.requireTxFails(s.vault.EmergencyWithdraw(s.signer, token, receiver.address(), amount))
+
+	tx, err := s.vault.RequestEmergencyWithdraw(s.signer, token, receiver.address(), amount)
+	readyAt := new(big.Int).Add(s.currentTimestamp(), bigInt(24*60*60))
+	s.requireTxWithStrictEvents(tx, err)(
+		abi.VaultEmergencyWithdrawRequested{
+			Token: token, To: receiver.address(), Amount: amount, ReadyAt: readyAt,
+		},
+	)
+
+	// Withdrawing before the timelock has passed fails.
+	s.requireTxFails(s.vault.EmergencyWithdraw(s.signer, token, receiver.address(), amount))
+	s.adjustTime(23 * time.Hour)
+	s.requireTxFails(s.vault.EmergencyWithdraw(s.signer, token, receiver.address(), amount))
+
+	// So does withdrawing anything other than what was requested.
+	s.adjustTime(1 * time.Hour)
+	s.requireTxFails(s.vault.EmergencyWithdraw(s.signer, token, receiver.address(), bigInt(301)))
+	s.requireTxFails(s.vault.EmergencyWithdraw(s.signer, token, s.owner.address(), amount))
+
+	s.requireTxWithStrictEvents(s.vault.EmergencyWithdraw(s.signer, token, receiver.address(), amount))(
+		abi.BasicERC20Transfer{From: s.vaultAddress, To: receiver.address(), Value: amount},
+		abi.VaultEmergencyWithdraw{Token: token, To: receiver.address(), Amount: amount},
+	)
+	s.assertERC20Balance(s.erc20s[0], receiver.address(), amount)
+
+	// A request can only be used once.
+	s.requireTxFails(s.vault.EmergencyWithdraw(s.signer, token, receiver.address(), amount))
+}
+
+// TestEmergencyWithdrawProtected makes sure only the owner can request or make emergency
+// withdrawals, even once the timelock has passed.
+func (s *VaultSuite) TestEmergencyWithdrawProtected() {
+	manager := s.account[1]
+	receiver := s.account[2]
+	token := s.erc20Addresses[0]
+	amount := bigInt(1)
+
+	s.requireTx(s.vault.ChangeManager(s.signer, manager.address()))
+
+	s.requireTxFails(s.vault.RequestEmergencyWithdraw(signer(manager), token, receiver.address(), amount))
+	s.requireTxFails(s.vault.RequestEmergencyWithdraw(signer(receiver), token, receiver.address(), amount))
+
+	s.requireTx(s.vault.RequestEmergencyWithdraw(s.signer, token, receiver.address(), amount))
+	s.adjustTime(24 * time.Hour)
+
+	s.requireTxFails(s.vault.EmergencyWithdraw(signer(manager), token, receiver.address(), amount))
+	s.requireTxFails(s.vault.EmergencyWithdraw(signer(receiver), token, receiver.address(), amount))
+	s.requireTx(s.vault.EmergencyWithdraw(s.signer, token, receiver.address(), amount))
+}
+
 ///
 func (s *VaultSuite) TestUpgrade() {
 	newKey := s.account[3]